@@ -0,0 +1,261 @@
+package certu
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// KeyTypes are the supported key types of generated certificates
+var KeyTypes = []string{"rsa2048", "rsa4096", "ecdsa_p256", "ecdsa_p384"}
+
+// Fingerprints are the fingerprints of a certificate, in the format of the
+// gridscale API (upper case hex, separated by colons)
+type Fingerprints struct {
+	MD5    string
+	SHA1   string
+	SHA256 string
+}
+
+// Bundle is a parsed private key, leaf certificate and certificate chain
+type Bundle struct {
+	Leaf  *x509.Certificate
+	Chain []*x509.Certificate
+}
+
+// SelfSignedOptions are the options of a generated self-signed certificate
+type SelfSignedOptions struct {
+	CommonName   string
+	Organization string
+	DNSNames     []string
+	IPAddresses  []string
+	KeyType      string
+	Validity     time.Duration
+}
+
+// ParseBundle parses the PEM encoded private key, leaf certificate and
+// certificate chain. It checks that the private key matches the leaf
+// certificate and, if validateChain is set, that the chain is ordered (every
+// certificate is issued by the next one) and complete (the last certificate
+// is self-signed or issued by a root of the system trust store).
+func ParseBundle(privateKeyPEM, leafPEM, chainPEM string, validateChain bool) (*Bundle, error) {
+	leafCerts, err := parseCertificates(leafPEM)
+	if err != nil {
+		return nil, fmt.Errorf("leaf_certificate: %v", err)
+	}
+	if len(leafCerts) != 1 {
+		return nil, fmt.Errorf("leaf_certificate: expected exactly one certificate, found %d", len(leafCerts))
+	}
+	if _, err = tls.X509KeyPair([]byte(leafPEM), []byte(privateKeyPEM)); err != nil {
+		return nil, fmt.Errorf("private_key does not match leaf_certificate: %v", err)
+	}
+	bundle := &Bundle{Leaf: leafCerts[0]}
+	if strings.TrimSpace(chainPEM) != "" {
+		if bundle.Chain, err = parseCertificates(chainPEM); err != nil {
+			return nil, fmt.Errorf("certificate_chain: %v", err)
+		}
+	}
+	if validateChain {
+		if err = bundle.validateChain(); err != nil {
+			return nil, fmt.Errorf("certificate_chain: %v", err)
+		}
+	}
+	return bundle, nil
+}
+
+// validateChain checks that every certificate is issued by the next one and
+// that the last one is self-signed or issued by a trusted root
+func (b *Bundle) validateChain() error {
+	certs := append([]*x509.Certificate{b.Leaf}, b.Chain...)
+	for i := 0; i+1 < len(certs); i++ {
+		if issuedBy(certs[i], certs[i+1]) {
+			continue
+		}
+		for j, cert := range certs {
+			if j != i && issuedBy(certs[i], cert) {
+				return fmt.Errorf("the chain is not ordered, %q is issued by %q, which has to follow it directly", subjectName(certs[i]), subjectName(cert))
+			}
+		}
+		return fmt.Errorf("%q is not issued by the next certificate %q, the issuer %q is missing", subjectName(certs[i]), subjectName(certs[i+1]), certs[i].Issuer.String())
+	}
+	last := certs[len(certs)-1]
+	if issuedBy(last, last) {
+		return nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return fmt.Errorf("could not load the system trust store to check the chain: %v", err)
+	}
+	_, err = last.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("the chain is incomplete, the issuer %q of %q is neither in the chain nor a trusted root", last.Issuer.String(), subjectName(last))
+	}
+	return nil
+}
+
+// CertificateFingerprints computes the MD5, SHA-1 and SHA-256 fingerprints of a certificate
+func CertificateFingerprints(cert *x509.Certificate) Fingerprints {
+	md5Sum := md5.Sum(cert.Raw)
+	sha1Sum := sha1.Sum(cert.Raw)
+	sha256Sum := sha256.Sum256(cert.Raw)
+	return Fingerprints{
+		MD5:    formatFingerprint(md5Sum[:]),
+		SHA1:   formatFingerprint(sha1Sum[:]),
+		SHA256: formatFingerprint(sha256Sum[:]),
+	}
+}
+
+// GenerateSelfSigned generates a private key and a self-signed certificate,
+// both PEM encoded
+func GenerateSelfSigned(opts SelfSignedOptions) (string, string, error) {
+	key, err := generateKey(opts.KeyType)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+	notBefore := time.Now().UTC().Truncate(time.Second)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: opts.CommonName,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(opts.Validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              opts.DNSNames,
+	}
+	if opts.Organization != "" {
+		template.Subject.Organization = []string{opts.Organization}
+	}
+	for _, ipStr := range opts.IPAddresses {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return "", "", fmt.Errorf("invalid IP address %q", ipStr)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+	// Clients match the host name against the SANs only, so the common name is added as well
+	if opts.CommonName != "" && net.ParseIP(opts.CommonName) == nil && !containsString(template.DNSNames, opts.CommonName) {
+		template.DNSNames = append([]string{opts.CommonName}, template.DNSNames...)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return strings.TrimSpace(keyPEM), strings.TrimSpace(string(certPEM)), nil
+}
+
+func generateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "rsa2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "rsa4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case "ecdsa_p256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa_p384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	}
+	return nil, fmt.Errorf("unsupported key type %q, supported key types are: %s", keyType, strings.Join(KeyTypes, ", "))
+}
+
+func encodePrivateKey(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+	}
+	return "", errors.New("unsupported private key")
+}
+
+// parseCertificates parses all PEM encoded certificates of a string
+func parseCertificates(pemStr string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(strings.TrimSpace(pemStr))
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, errors.New("invalid PEM data")
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %q, expected CERTIFICATE", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+		rest = []byte(strings.TrimSpace(string(rest)))
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+// issuedBy checks if cert is signed by parent. In contrast to CheckSignatureFrom
+// it does not check the constraints of parent, so that self-signed leaf
+// certificates and older signature algorithms are accepted.
+func issuedBy(cert, parent *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, parent.RawSubject) &&
+		parent.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+func subjectName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func containsString(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
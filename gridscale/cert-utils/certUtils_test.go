@@ -0,0 +1,124 @@
+package certu
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSigned(t *testing.T) {
+	for _, keyType := range []string{"rsa2048", "ecdsa_p256", "ecdsa_p384"} {
+		keyPEM, certPEM, err := GenerateSelfSigned(SelfSignedOptions{
+			CommonName:  "lb.internal",
+			DNSNames:    []string{"www.lb.internal"},
+			IPAddresses: []string{"10.0.0.1"},
+			KeyType:     keyType,
+			Validity:    24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", keyType, err)
+		}
+		bundle, err := ParseBundle(keyPEM, certPEM, "", true)
+		if err != nil {
+			t.Fatalf("%s: unexpected error parsing the generated certificate: %v", keyType, err)
+		}
+		if bundle.Leaf.Subject.CommonName != "lb.internal" {
+			t.Errorf("%s: expected common name lb.internal, got %s", keyType, bundle.Leaf.Subject.CommonName)
+		}
+		if len(bundle.Leaf.DNSNames) != 2 || len(bundle.Leaf.IPAddresses) != 1 {
+			t.Errorf("%s: unexpected SANs %v %v", keyType, bundle.Leaf.DNSNames, bundle.Leaf.IPAddresses)
+		}
+		if fingerprint := CertificateFingerprints(bundle.Leaf).SHA256; len(fingerprint) != 95 || strings.ToUpper(fingerprint) != fingerprint {
+			t.Errorf("%s: unexpected fingerprint format %s", keyType, fingerprint)
+		}
+	}
+	if _, _, err := GenerateSelfSigned(SelfSignedOptions{CommonName: "x", KeyType: "dsa"}); err == nil {
+		t.Error("expected an error for an unsupported key type")
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	root := testCertificate(t, "root", nil, true)
+	intermediate := testCertificate(t, "intermediate", root, true)
+	leaf := testCertificate(t, "leaf", intermediate, false)
+	other := testCertificate(t, "other", nil, false)
+
+	type testCase struct {
+		Name          string
+		PrivateKey    string
+		Chain         string
+		ValidateChain bool
+		ExpectedError string
+	}
+	testCases := []testCase{
+		{Name: "complete chain", PrivateKey: leaf.keyPEM, Chain: intermediate.certPEM + root.certPEM, ValidateChain: true},
+		{Name: "key mismatch", PrivateKey: other.keyPEM, Chain: intermediate.certPEM + root.certPEM, ValidateChain: true, ExpectedError: "private_key does not match"},
+		{Name: "unordered chain", PrivateKey: leaf.keyPEM, Chain: root.certPEM + intermediate.certPEM, ValidateChain: true, ExpectedError: "not ordered"},
+		{Name: "missing intermediate", PrivateKey: leaf.keyPEM, Chain: root.certPEM, ValidateChain: true, ExpectedError: "is missing"},
+		{Name: "missing root", PrivateKey: leaf.keyPEM, Chain: intermediate.certPEM, ValidateChain: true, ExpectedError: "incomplete"},
+		{Name: "chain not validated", PrivateKey: leaf.keyPEM, Chain: root.certPEM, ValidateChain: false},
+	}
+	for _, test := range testCases {
+		_, err := ParseBundle(test.PrivateKey, leaf.certPEM, test.Chain, test.ValidateChain)
+		switch {
+		case test.ExpectedError == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.Name, err)
+		case test.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), test.ExpectedError)):
+			t.Errorf("%s: expected error containing %q, got %v", test.Name, test.ExpectedError, err)
+		}
+	}
+}
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// testCertificate creates a certificate issued by parent, or a self-signed one if parent is nil
+func testCertificate(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	parentCert, signer := template, key
+	if parent != nil {
+		parentCert, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
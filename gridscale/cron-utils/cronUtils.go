@@ -0,0 +1,184 @@
+package cronu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// schedules are computed in the time zone of the user, which must not depend on the zone database of the host
+	_ "time/tzdata"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// macros are the supported shorthands of cron expressions
+var macros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+}
+
+// weekdayNames are the names of the days of the week which can be used instead of 0-6
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Schedule is a cron expression which runs at a fixed interval. The backend of snapshot and
+// backup schedules only supports a next runtime and a run interval, so only these expressions
+// can be represented.
+type Schedule struct {
+	minute   int
+	hours    []int
+	weekdays [7]bool
+	interval int
+}
+
+// Parse parses a cron expression ("minute hour day-of-month month day-of-week") and checks
+// that its runs are evenly spaced, so that it can be represented by a run interval.
+// Day of month and month have to be "*".
+func Parse(expr string) (Schedule, error) {
+	var s Schedule
+	if macro, ok := macros[strings.ToLower(strings.TrimSpace(expr))]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return s, fmt.Errorf("cron expression %q must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+	minutes, err := parseField(fields[0], 0, 59, nil)
+	if err != nil {
+		return s, fmt.Errorf("invalid minute field %q: %v", fields[0], err)
+	}
+	if len(minutes) != 1 {
+		return s, fmt.Errorf("cron expression %q runs more than once per hour, the run interval has to be at least 60 minutes", expr)
+	}
+	s.minute = minutes[0]
+	if s.hours, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return s, fmt.Errorf("invalid hour field %q: %v", fields[1], err)
+	}
+	if fields[2] != "*" || fields[3] != "*" {
+		return s, fmt.Errorf("cron expression %q restricts the day of month or the month, which can not be represented by a run interval", expr)
+	}
+	weekdays, err := parseField(fields[4], 0, 7, weekdayNames)
+	if err != nil {
+		return s, fmt.Errorf("invalid day-of-week field %q: %v", fields[4], err)
+	}
+	for _, weekday := range weekdays {
+		// 7 is Sunday as well
+		s.weekdays[weekday%7] = true
+	}
+
+	runs := s.runsOfWeek()
+	s.interval = runs[0] + minutesPerWeek - runs[len(runs)-1]
+	for i := 1; i < len(runs); i++ {
+		if runs[i]-runs[i-1] != s.interval {
+			return s, fmt.Errorf("cron expression %q runs at irregular intervals, it can not be represented by a run interval", expr)
+		}
+	}
+	return s, nil
+}
+
+// parseField parses a field of a cron expression, e.g. "*", "*/6", "1-5", "2,14" or "mon-fri",
+// and returns the sorted values
+func parseField(field string, min, max int, names map[string]int) ([]int, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			rangePart = part[:i]
+		}
+		start, end := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				// "a/n" means from a to the maximum
+				end = max
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	result := make([]int, 0, len(values))
+	for v := range values {
+		result = append(result, v)
+	}
+	sort.Ints(result)
+	return result, nil
+}
+
+// parseValue parses a single value of a cron field
+func parseValue(value string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// runsOfWeek returns the runs of the schedule as sorted minutes since Sunday 00:00
+func (s Schedule) runsOfWeek() []int {
+	var runs []int
+	for weekday, ok := range s.weekdays {
+		if !ok {
+			continue
+		}
+		for _, hour := range s.hours {
+			runs = append(runs, weekday*minutesPerDay+hour*60+s.minute)
+		}
+	}
+	return runs
+}
+
+// Interval returns the run interval of the schedule in minutes
+func (s Schedule) Interval() int {
+	return s.interval
+}
+
+// Next returns the first run of the schedule after the given time. The schedule is evaluated
+// in the given location, so that a run at 02:30 stays at 02:30 local time across DST changes.
+func (s Schedule) Next(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+	// a weekly schedule runs within the next 8 days
+	for day := 0; day <= 7; day++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, loc)
+		if !s.weekdays[date.Weekday()] {
+			continue
+		}
+		for _, hour := range s.hours {
+			run := time.Date(date.Year(), date.Month(), date.Day(), hour, s.minute, 0, 0, loc)
+			if run.After(after) {
+				return run
+			}
+		}
+	}
+	return time.Time{}
+}
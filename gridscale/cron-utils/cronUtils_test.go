@@ -0,0 +1,73 @@
+package cronu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		Expression string
+		Interval   int
+		Valid      bool
+	}
+	testCases := []testCase{
+		{"30 2 * * *", 1440, true},
+		{"@daily", 1440, true},
+		{"15 * * * *", 60, true},
+		{"0 */6 * * *", 360, true},
+		{"0 3,15 * * *", 720, true},
+		{"0 4 * * sun", 10080, true},
+		{"0 4 * * 7", 10080, true},
+		{"0 0 * * 1,3,5", 0, false},
+		{"0 0 * * 1-5", 0, false},
+		{"0 1,2 * * *", 0, false},
+		{"*/30 * * * *", 0, false},
+		{"0 2 1 * *", 0, false},
+		{"0 2 * 6 *", 0, false},
+		{"0 24 * * *", 0, false},
+		{"0 2 * *", 0, false},
+	}
+	for _, test := range testCases {
+		schedule, err := Parse(test.Expression)
+		if test.Valid != (err == nil) {
+			t.Errorf("%q: expected valid %v, got error %v", test.Expression, test.Valid, err)
+			continue
+		}
+		if test.Valid && schedule.Interval() != test.Interval {
+			t.Errorf("%q: expected interval %d, got %d", test.Expression, test.Interval, schedule.Interval())
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type testCase struct {
+		Expression string
+		After      string
+		Expected   string
+	}
+	testCases := []testCase{
+		// 02:30 CEST is 00:30 UTC, 02:30 CET is 01:30 UTC
+		{"30 2 * * *", "2026-10-24T12:00:00Z", "2026-10-25T01:30:00Z"},
+		{"30 2 * * *", "2026-10-23T12:00:00Z", "2026-10-24T00:30:00Z"},
+		{"30 2 * * *", "2026-10-24T00:30:00Z", "2026-10-25T01:30:00Z"},
+		{"0 */6 * * *", "2026-06-01T05:00:00Z", "2026-06-01T10:00:00Z"},
+		// 2026-06-01 is a Monday
+		{"0 4 * * sun", "2026-06-01T12:00:00Z", "2026-06-07T02:00:00Z"},
+	}
+	for _, test := range testCases {
+		schedule, err := Parse(test.Expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, _ := time.Parse(time.RFC3339, test.After)
+		next := schedule.Next(after, berlin).UTC().Format(time.RFC3339)
+		if next != test.Expected {
+			t.Errorf("%q after %s: expected %s, got %s", test.Expression, test.After, test.Expected, next)
+		}
+	}
+}
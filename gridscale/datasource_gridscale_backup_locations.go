@@ -0,0 +1,75 @@
+package gridscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGridscaleBackupLocations() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGridscaleBackupLocationsRead,
+		Schema: map[string]*schema.Schema{
+			"locations": {
+				Type:        schema.TypeList,
+				Description: "All locations backups can be stored in, sorted by name.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_uuid": {
+							Type:        schema.TypeString,
+							Description: "UUID of the backup location.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the backup location.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"location_uuids": {
+				Type:        schema.TypeList,
+				Description: "UUIDs of all backup locations, sorted by name.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceGridscaleBackupLocationsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := "read backup locations datasource -"
+
+	backupLocations, err := client.GetStorageBackupLocationList(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	sort.SliceStable(backupLocations, func(i, j int) bool {
+		return backupLocations[i].Properties.Name < backupLocations[j].Properties.Name
+	})
+	locations := make([]interface{}, 0, len(backupLocations))
+	locationUUIDs := make([]string, 0, len(backupLocations))
+	for _, location := range backupLocations {
+		locations = append(locations, map[string]interface{}{
+			"object_uuid": location.Properties.ObjectUUID,
+			"name":        location.Properties.Name,
+		})
+		locationUUIDs = append(locationUUIDs, location.Properties.ObjectUUID)
+	}
+	if err = d.Set("locations", locations); err != nil {
+		return fmt.Errorf("%s error setting locations: %v", errorPrefix, err)
+	}
+	if err = d.Set("location_uuids", locationUUIDs); err != nil {
+		return fmt.Errorf("%s error setting location_uuids: %v", errorPrefix, err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(locationUUIDs, ",")))))
+	return nil
+}
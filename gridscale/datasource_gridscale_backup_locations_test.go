@@ -0,0 +1,34 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceGridscaleBackupLocationsBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceGridscaleBackupLocationsConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_backup_locations.test", "locations.0.object_uuid"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_backup_locations.test", "locations.0.name"),
+					resource.TestCheckResourceAttrPair(
+						"data.gridscale_backup_locations.test", "location_uuids.0",
+						"data.gridscale_backup_locations.test", "locations.0.object_uuid"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceGridscaleBackupLocationsConfigBasic() string {
+	return `
+data "gridscale_backup_locations" "test" {
+}`
+}
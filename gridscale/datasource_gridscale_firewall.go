@@ -119,6 +119,16 @@ func dataSourceGridscaleFirewall() *schema.Resource {
 				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"iptables_export": {
+				Type:        schema.TypeString,
+				Description: "The IPv4 rules in iptables-save format.",
+				Computed:    true,
+			},
+			"ip6tables_export": {
+				Type:        schema.TypeString,
+				Description: "The IPv6 rules in ip6tables-save format.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -204,5 +214,13 @@ func dataSourceGridscaleFirewallRead(d *schema.ResourceData, meta interface{}) e
 	if err = d.Set("labels", props.Labels); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
+
+	//Export rules in iptables-save format, without default inbound rules
+	if err = d.Set("iptables_export", fwu.ExportIptablesSave(rulesV4InWODefaultRules, props.Rules.RulesV4Out)); err != nil {
+		return fmt.Errorf("%s error setting iptables_export: %v", errorPrefix, err)
+	}
+	if err = d.Set("ip6tables_export", fwu.ExportIptablesSave(rulesV6InWODefaultRules, props.Rules.RulesV6Out)); err != nil {
+		return fmt.Errorf("%s error setting ip6tables_export: %v", errorPrefix, err)
+	}
 	return nil
 }
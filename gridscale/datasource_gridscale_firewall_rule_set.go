@@ -0,0 +1,138 @@
+package gridscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	fwu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/firewall-utils"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGridscaleFirewallRuleSet() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGridscaleFirewallRuleSetRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The name of the rule set. It is used in conflict messages.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"include": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rule sets (rule_set attribute of other gridscale_firewall_rule_set data sources) whose rules are placed before the rules of this rule set, in sequence.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateFirewallRuleSet,
+				},
+			},
+			"rules_v4_in": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleSetRuleSchema(),
+				},
+			},
+			"rules_v4_out": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleSetRuleSchema(),
+				},
+			},
+			"rules_v6_in": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleSetRuleSchema(),
+				},
+			},
+			"rules_v6_out": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleSetRuleSchema(),
+				},
+			},
+			"rule_set": {
+				Type:        schema.TypeString,
+				Description: "The encoded rule set, to be used in the rule_sets argument of gridscale_firewall and of server networks.",
+				Computed:    true,
+			},
+			"rule_count": {
+				Type:        schema.TypeInt,
+				Description: "The number of rules in the rule set (including included rule sets).",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// getFirewallRuleSetRuleSchema returns the schema of a rule of a rule set.
+// In contrast to getFirewallRuleCommonSchema the order is optional, rules
+// are renumbered in the sequence of their order (or declaration).
+func getFirewallRuleSetRuleSchema() map[string]*schema.Schema {
+	ruleSchema := getFirewallRuleCommonSchema()
+	ruleSchema["order"].Required = false
+	ruleSchema["order"].Optional = true
+	ruleSchema["order"].Description = "The relative order of the rule within the rule set. Rules are renumbered starting from 0, rules with the same order keep the sequence of their declaration."
+	return ruleSchema
+}
+
+// validateFirewallRuleSet validates an encoded rule set
+func validateFirewallRuleSet(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := fwu.DecodeRuleSet(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %v", k, err))
+	}
+	return
+}
+
+func dataSourceGridscaleFirewallRuleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	errorPrefix := fmt.Sprintf("read firewall rule set (%s) datasource -", name)
+
+	ruleSets, err := fwu.DecodeRuleSets(convSOStrings(d.Get("include").([]interface{})))
+	if err != nil {
+		return diag.Errorf("%s error: %v", errorPrefix, err)
+	}
+	ownRules := gsclient.FirewallRules{
+		RulesV4In:  fwu.RenumberRules(convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v4_in").([]interface{}))),
+		RulesV4Out: fwu.RenumberRules(convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v4_out").([]interface{}))),
+		RulesV6In:  fwu.RenumberRules(convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v6_in").([]interface{}))),
+		RulesV6Out: fwu.RenumberRules(convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v6_out").([]interface{}))),
+	}
+	ruleSets = append(ruleSets, fwu.RuleSet{Name: name, Rules: ownRules})
+
+	// Merge included rule sets and own rules, this renumbers all rules
+	rules, conflicts := fwu.MergeRuleSets(gsclient.FirewallRules{}, ruleSets)
+	var diags diag.Diagnostics
+	for _, conflict := range conflicts {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Firewall rule set conflict",
+			Detail:   fmt.Sprintf("rule set %q: %s", name, conflict),
+		})
+	}
+
+	ruleSet, err := fwu.EncodeRuleSet(fwu.RuleSet{Name: name, Rules: rules})
+	if err != nil {
+		return diag.Errorf("%s error encoding rule set: %v", errorPrefix, err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(ruleSet))))
+
+	if err = d.Set("rule_set", ruleSet); err != nil {
+		return diag.Errorf("%s error setting rule_set: %v", errorPrefix, err)
+	}
+	ruleCount := len(rules.RulesV4In) + len(rules.RulesV4Out) + len(rules.RulesV6In) + len(rules.RulesV6Out)
+	if err = d.Set("rule_count", ruleCount); err != nil {
+		return diag.Errorf("%s error setting rule_count: %v", errorPrefix, err)
+	}
+	return diags
+}
@@ -0,0 +1,74 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccdataSourceGridscaleFirewallRuleSetBasic(t *testing.T) {
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleFirewallDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceFirewallRuleSetConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_firewall_rule_set.ssh", "rule_set"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rule_set.ssh", "rule_count", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rule_set.web", "rule_count", "3"),
+					resource.TestCheckResourceAttr("gridscale_firewall.foo", "name", name),
+					resource.TestCheckResourceAttr("gridscale_firewall.foo", "rules_v4_in.#", "1"),
+					resource.TestCheckResourceAttr("gridscale_firewall.foo", "rule_set_conflicts.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceFirewallRuleSetConfigBasic(name string) string {
+	return fmt.Sprintf(`
+data "gridscale_firewall_rule_set" "ssh" {
+  name = "ssh-from-office"
+  rules_v4_in {
+    protocol = "tcp"
+    action   = "accept"
+    dst_port = "22"
+    src_cidr = "10.0.0.0/8"
+    comment  = "ssh from office"
+  }
+}
+
+data "gridscale_firewall_rule_set" "web" {
+  name    = "web"
+  include = [data.gridscale_firewall_rule_set.ssh.rule_set]
+  rules_v4_in {
+    protocol = "tcp"
+    action   = "accept"
+    dst_port = "443"
+  }
+  rules_v4_in {
+    protocol = "tcp"
+    action   = "accept"
+    dst_port = "80"
+  }
+}
+
+resource "gridscale_firewall" "foo" {
+  name = "%s"
+  rules_v4_in {
+    order    = 0
+    protocol = "tcp"
+    action   = "drop"
+    dst_port = "80"
+    comment  = "no plain http"
+  }
+  rule_sets = [data.gridscale_firewall_rule_set.web.rule_set]
+}
+`, name)
+}
@@ -0,0 +1,162 @@
+package gridscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	fwu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/firewall-utils"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var firewallRulesTextInputs = []string{"iptables_v4", "iptables_v6", "nftables"}
+
+func dataSourceGridscaleFirewallRulesFromText() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGridscaleFirewallRulesFromTextRead,
+
+		Schema: map[string]*schema.Schema{
+			"iptables_v4": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Output of iptables-save. The INPUT and OUTPUT chains of the filter table are translated to rules_v4_in and rules_v4_out.",
+				ConflictsWith: []string{"nftables"},
+				AtLeastOneOf:  firewallRulesTextInputs,
+			},
+			"iptables_v6": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Output of ip6tables-save. The INPUT and OUTPUT chains of the filter table are translated to rules_v6_in and rules_v6_out.",
+				ConflictsWith: []string{"nftables"},
+				AtLeastOneOf:  firewallRulesTextInputs,
+			},
+			"nftables": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Output of nft list ruleset. The filter chains hooked to input and output are translated, ip tables to IPv4 rules, ip6 tables to IPv6 rules and inet tables to both.",
+				ConflictsWith: []string{"iptables_v4", "iptables_v6"},
+				AtLeastOneOf:  firewallRulesTextInputs,
+			},
+			"strict": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, rules which cannot be translated cause an error instead of a warning.",
+			},
+			"rules_v4_in": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleCommonSchema(),
+				},
+			},
+			"rules_v4_out": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleCommonSchema(),
+				},
+			},
+			"rules_v6_in": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleCommonSchema(),
+				},
+			},
+			"rules_v6_out": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: getFirewallRuleCommonSchema(),
+				},
+			},
+			"skipped_rules": {
+				Type:        schema.TypeList,
+				Description: "Rules of the input which could not be translated, with the reason.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceGridscaleFirewallRulesFromTextRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	errorPrefix := "read firewall rules from text datasource -"
+
+	var rules fwu.HostRules
+	var ruleDiags []fwu.RuleDiagnostic
+	if ruleset, ok := d.GetOk("nftables"); ok {
+		var err error
+		rules, ruleDiags, err = fwu.ParseNftRuleset(ruleset.(string))
+		if err != nil {
+			return diag.Errorf("%s error parsing nftables: %v", errorPrefix, err)
+		}
+	}
+	if ruleset, ok := d.GetOk("iptables_v4"); ok {
+		v4Rules, v4Diags, err := fwu.ParseIptablesSave(ruleset.(string), false)
+		if err != nil {
+			return diag.Errorf("%s error parsing iptables_v4: %v", errorPrefix, err)
+		}
+		rules.RulesV4In, rules.RulesV4Out = v4Rules.RulesV4In, v4Rules.RulesV4Out
+		ruleDiags = append(ruleDiags, v4Diags...)
+	}
+	if ruleset, ok := d.GetOk("iptables_v6"); ok {
+		v6Rules, v6Diags, err := fwu.ParseIptablesSave(ruleset.(string), true)
+		if err != nil {
+			return diag.Errorf("%s error parsing iptables_v6: %v", errorPrefix, err)
+		}
+		rules.RulesV6In, rules.RulesV6Out = v6Rules.RulesV6In, v6Rules.RulesV6Out
+		ruleDiags = append(ruleDiags, v6Diags...)
+	}
+
+	var diags diag.Diagnostics
+	skippedRules := make([]string, 0)
+	strict := d.Get("strict").(bool)
+	for _, ruleDiag := range ruleDiags {
+		severity := diag.Warning
+		summary := "Firewall rule translated with approximation"
+		if ruleDiag.Skipped {
+			skippedRules = append(skippedRules, ruleDiag.String())
+			summary = "Unsupported firewall rule skipped"
+			if strict {
+				severity = diag.Error
+				summary = "Unsupported firewall rule"
+			}
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: severity,
+			Summary:  summary,
+			Detail:   ruleDiag.String(),
+		})
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	hash := sha256.New()
+	for _, key := range firewallRulesTextInputs {
+		hash.Write([]byte(d.Get(key).(string)))
+		hash.Write([]byte{0})
+	}
+	d.SetId(fmt.Sprintf("%x", hash.Sum(nil)))
+
+	if err := d.Set("rules_v4_in", convFirewallRuleSliceToInterfaceSlice(rules.RulesV4In)); err != nil {
+		return diag.Errorf("%s error setting rules_v4_in: %v", errorPrefix, err)
+	}
+	if err := d.Set("rules_v4_out", convFirewallRuleSliceToInterfaceSlice(rules.RulesV4Out)); err != nil {
+		return diag.Errorf("%s error setting rules_v4_out: %v", errorPrefix, err)
+	}
+	if err := d.Set("rules_v6_in", convFirewallRuleSliceToInterfaceSlice(rules.RulesV6In)); err != nil {
+		return diag.Errorf("%s error setting rules_v6_in: %v", errorPrefix, err)
+	}
+	if err := d.Set("rules_v6_out", convFirewallRuleSliceToInterfaceSlice(rules.RulesV6Out)); err != nil {
+		return diag.Errorf("%s error setting rules_v6_out: %v", errorPrefix, err)
+	}
+	if err := d.Set("skipped_rules", skippedRules); err != nil {
+		return diag.Errorf("%s error setting skipped_rules: %v", errorPrefix, err)
+	}
+	return diags
+}
@@ -0,0 +1,113 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccdataSourceGridscaleFirewallRulesFromTextBasic(t *testing.T) {
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleFirewallDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceFirewallRulesFromTextConfigIptables(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "rules_v4_in.#", "3"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "rules_v4_in.0.dst_port", "22"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "rules_v4_in.0.comment", "ssh"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "skipped_rules.#", "1"),
+					resource.TestCheckResourceAttr("gridscale_firewall.foo", "rules_v4_in.#", "3"),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceFirewallRulesFromTextConfigNftables(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "rules_v4_in.#", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "rules_v6_in.#", "2"),
+					resource.TestCheckResourceAttr("data.gridscale_firewall_rules_from_text.foo", "skipped_rules.#", "0"),
+					resource.TestCheckResourceAttr("gridscale_firewall.foo", "rules_v6_in.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceFirewallRulesFromTextConfigIptables(name string) string {
+	return fmt.Sprintf(`
+data "gridscale_firewall_rules_from_text" "foo" {
+  iptables_v4 = <<-EOT
+    *filter
+    :INPUT DROP [0:0]
+    :FORWARD DROP [0:0]
+    :OUTPUT ACCEPT [0:0]
+    -A INPUT -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT
+    -A INPUT -s 10.0.0.0/8 -p tcp -m tcp --dport 22 -m comment --comment "ssh" -j ACCEPT
+    -A INPUT -p tcp -m multiport --dports 80,443 -j ACCEPT
+    COMMIT
+  EOT
+}
+
+resource "gridscale_firewall" "foo" {
+  name = "%s"
+  dynamic "rules_v4_in" {
+    for_each = data.gridscale_firewall_rules_from_text.foo.rules_v4_in
+    content {
+      order    = rules_v4_in.value.order
+      protocol = rules_v4_in.value.protocol
+      action   = rules_v4_in.value.action
+      dst_port = rules_v4_in.value.dst_port
+      src_cidr = rules_v4_in.value.src_cidr
+      comment  = rules_v4_in.value.comment
+    }
+  }
+}
+`, name)
+}
+
+func testAccCheckDataSourceFirewallRulesFromTextConfigNftables(name string) string {
+	return fmt.Sprintf(`
+data "gridscale_firewall_rules_from_text" "foo" {
+  nftables = <<-EOT
+    table inet filter {
+      chain input {
+        type filter hook input priority filter; policy drop;
+        ip6 saddr fd00::/8 udp dport 53 accept
+        tcp dport 443 accept comment "https"
+      }
+    }
+  EOT
+}
+
+resource "gridscale_firewall" "foo" {
+  name = "%s"
+  dynamic "rules_v4_in" {
+    for_each = data.gridscale_firewall_rules_from_text.foo.rules_v4_in
+    content {
+      order    = rules_v4_in.value.order
+      protocol = rules_v4_in.value.protocol
+      action   = rules_v4_in.value.action
+      dst_port = rules_v4_in.value.dst_port
+      comment  = rules_v4_in.value.comment
+    }
+  }
+  dynamic "rules_v6_in" {
+    for_each = data.gridscale_firewall_rules_from_text.foo.rules_v6_in
+    content {
+      order    = rules_v6_in.value.order
+      protocol = rules_v6_in.value.protocol
+      action   = rules_v6_in.value.action
+      dst_port = rules_v6_in.value.dst_port
+      src_cidr = rules_v6_in.value.src_cidr
+      comment  = rules_v6_in.value.comment
+    }
+  }
+}
+`, name)
+}
@@ -22,6 +22,7 @@ func TestAccdataSourceGridscaleFirewallBasic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.gridscale_firewall.foo", "id"),
 					resource.TestCheckResourceAttr("data.gridscale_firewall.foo", "name", name),
+					resource.TestCheckResourceAttr("data.gridscale_firewall.foo", "iptables_export", "*filter\n:INPUT DROP [0:0]\n:FORWARD ACCEPT [0:0]\n:OUTPUT ACCEPT [0:0]\n-A INPUT -p tcp -m tcp --dport 20:80 -m comment --comment \"test\" -j DROP\nCOMMIT\n"),
 				),
 			},
 		},
@@ -3,7 +3,6 @@ package gridscale
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/gridscale/gsclient-go/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -62,32 +61,21 @@ func dataSourceGridscaleK8sRead(d *schema.ResourceData, meta interface{}) error
 		}
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
-	creds := paas.Properties.Credentials
-	if len(creds) > 0 {
-		// if expiration_time of kubeconfig is reached, renew it and get new kubeconfig
-		if creds[0].ExpirationTime.Before(time.Now()) {
-			err = client.RenewK8sCredentials(context.Background(), d.Id())
-			if err != nil {
-				return fmt.Errorf("%s error renewing k8s kubeconfig: %v", errorPrefix, err)
-			}
-			paas, err = client.GetPaaSService(context.Background(), d.Id())
-			if err != nil {
-				return fmt.Errorf("%s error: %v", errorPrefix, err)
-			}
-			creds = paas.Properties.Credentials
-		}
+	// if expiration_time of kubeconfig is reached, renew it and get new kubeconfig
+	paas, err = renewExpiredK8sCredentials(client, paas, 0)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	if creds := paas.Properties.Credentials; len(creds) > 0 {
 		if err = d.Set("kubeconfig", creds[0].KubeConfig); err != nil {
 			return fmt.Errorf("%s error setting kubeconfig: %v", errorPrefix, err)
 		}
 	}
-
 	if err = d.Set("name", paas.Properties.Name); err != nil {
 		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
 	}
-	if err = d.Set("kubeconfig", creds[0].KubeConfig); err != nil {
-		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
-	}
-	if err = d.Set("labels", paas.Properties.Labels); err != nil {
+	// the labels marking the node pools of gridscale_k8s_node_pool resources are not shown
+	if err = d.Set("labels", removeK8sNodePoolLabels(paas.Properties.Labels)); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
 	networks, err := client.GetNetworkList(context.Background())
@@ -0,0 +1,155 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	kcu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/kubeconfig-utils"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGridscaleK8sCredentials() *schema.Resource {
+	credentialsSchema := k8sCredentialsSchema()
+	credentialsSchema["cluster_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		Description:  "ID of the k8s cluster.",
+		ValidateFunc: validation.NoZeroValues,
+	}
+	credentialsSchema["credential_renewal_threshold"] = k8sCredentialRenewalThresholdSchema()
+	credentialsSchema["kubeconfig"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "K8s config data",
+		Computed:    true,
+		Sensitive:   true,
+	}
+	return &schema.Resource{
+		Read:   dataSourceGridscaleK8sCredentialsRead,
+		Schema: credentialsSchema,
+	}
+}
+
+// k8sCredentialRenewalThresholdSchema returns the schema of the threshold for renewing the credentials of a k8s cluster
+func k8sCredentialRenewalThresholdSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "The kubeconfig is renewed on refresh if it expires within this duration, e.g. \"24h\". By default it is renewed once it has expired.",
+		Optional:     true,
+		Default:      "0s",
+		ValidateFunc: validateDuration,
+	}
+}
+
+// k8sCredentialsSchema returns the schema of the credentials parsed from the kubeconfig of a k8s cluster
+func k8sCredentialsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"host": {
+			Type:        schema.TypeString,
+			Description: "The URL of the k8s API server.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"cluster_ca_certificate": {
+			Type:        schema.TypeString,
+			Description: "The PEM-encoded CA certificate of the k8s API server.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"client_certificate": {
+			Type:        schema.TypeString,
+			Description: "The PEM-encoded client certificate to authenticate to the k8s API server.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"client_key": {
+			Type:        schema.TypeString,
+			Description: "The PEM-encoded client key to authenticate to the k8s API server.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"token": {
+			Type:        schema.TypeString,
+			Description: "The token to authenticate to the k8s API server.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"kubeconfig_expiration_time": {
+			Type:        schema.TypeString,
+			Description: "The date and time the kubeconfig expires.",
+			Computed:    true,
+		},
+	}
+}
+
+func dataSourceGridscaleK8sCredentialsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	id := d.Get("cluster_id").(string)
+	errorPrefix := fmt.Sprintf("read k8s credentials (%s) datasource -", id)
+
+	paas, err := client.GetPaaSService(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	threshold, _ := time.ParseDuration(d.Get("credential_renewal_threshold").(string))
+	paas, err = renewExpiredK8sCredentials(client, paas, threshold)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	if len(paas.Properties.Credentials) == 0 {
+		return fmt.Errorf("%s error: the k8s cluster has no credentials", errorPrefix)
+	}
+	d.SetId(id)
+	return setK8sCredentials(d, paas.Properties.Credentials[0])
+}
+
+// renewExpiredK8sCredentials renews the credentials of a k8s cluster if they expire within
+// the threshold and returns the k8s cluster with the new credentials
+func renewExpiredK8sCredentials(client *gsclient.Client, paas gsclient.PaaSService, threshold time.Duration) (gsclient.PaaSService, error) {
+	creds := paas.Properties.Credentials
+	if len(creds) == 0 || !creds[0].ExpirationTime.Before(time.Now().Add(threshold)) {
+		return paas, nil
+	}
+	log.Printf("[DEBUG] The kubeconfig of k8s cluster %s expires at %s, it is renewed", paas.Properties.ObjectUUID, creds[0].ExpirationTime.String())
+	id := paas.Properties.ObjectUUID
+	if err := client.RenewK8sCredentials(context.Background(), id); err != nil {
+		return paas, fmt.Errorf("error renewing k8s kubeconfig: %v", err)
+	}
+	paas, err := client.GetPaaSService(context.Background(), id)
+	if err != nil {
+		return paas, fmt.Errorf("error: %v", err)
+	}
+	return paas, nil
+}
+
+// setK8sCredentials sets the kubeconfig and the credentials parsed from it
+func setK8sCredentials(d *schema.ResourceData, cred gsclient.Credential) error {
+	if err := d.Set("kubeconfig", cred.KubeConfig); err != nil {
+		return fmt.Errorf("error setting kubeconfig: %v", err)
+	}
+	if err := d.Set("kubeconfig_expiration_time", cred.ExpirationTime.String()); err != nil {
+		return fmt.Errorf("error setting kubeconfig_expiration_time: %v", err)
+	}
+	parsed, err := kcu.ParseCredentials(cred.KubeConfig)
+	if err != nil {
+		// the kubeconfig attribute is still usable, so the read does not fail
+		log.Printf("[WARN] the credentials could not be parsed from the kubeconfig: %v", err)
+	}
+	values := map[string]string{
+		"host":                   parsed.Host,
+		"cluster_ca_certificate": parsed.ClusterCACertificate,
+		"client_certificate":     parsed.ClientCertificate,
+		"client_key":             parsed.ClientKey,
+		"token":                  parsed.Token,
+	}
+	for key, value := range values {
+		if err = d.Set(key, value); err != nil {
+			return fmt.Errorf("error setting %s: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -2,10 +2,12 @@ package gridscale
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccDataSourceGridscaleK8sBasic(t *testing.T) {
@@ -25,6 +27,16 @@ func TestAccDataSourceGridscaleK8sBasic(t *testing.T) {
 						"data.gridscale_k8s.test", "kubeconfig"),
 					resource.TestCheckResourceAttrSet(
 						"data.gridscale_k8s.test", "labels"),
+					testAccCheckDataSourceGridscaleK8sNoNodePoolLabels("data.gridscale_k8s.test"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_credentials.test", "host"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_credentials.test", "cluster_ca_certificate"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_credentials.test", "kubeconfig_expiration_time"),
+					resource.TestCheckResourceAttrPair(
+						"data.gridscale_k8s_credentials.test", "host",
+						"gridscale_k8s.test", "host"),
 				),
 			},
 		},
@@ -47,7 +59,38 @@ resource "gridscale_k8s" "test" {
 	}
 }
 
+resource "gridscale_k8s_node_pool" "test" {
+	cluster_id = gridscale_k8s.test.id
+	name = "pool-1"
+	node_count = 1
+	cores = 2
+	memory = 4
+	storage = 30
+	storage_type = "storage_insane"
+}
+
 data "gridscale_k8s" "test" {
     resource_id = gridscale_k8s.test.id
+    depends_on = [gridscale_k8s_node_pool.test]
+}
+
+data "gridscale_k8s_credentials" "test" {
+    cluster_id = gridscale_k8s.test.id
 }`, name)
 }
+
+// testAccCheckDataSourceGridscaleK8sNoNodePoolLabels checks that the labels marking node pools are not shown
+func testAccCheckDataSourceGridscaleK8sNoNodePoolLabels(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		for key, value := range rs.Primary.Attributes {
+			if strings.HasPrefix(key, "labels.") && strings.HasPrefix(value, k8sNodePoolLabelPrefix) {
+				return fmt.Errorf("label %s of node pool is shown in %s", value, key)
+			}
+		}
+		return nil
+	}
+}
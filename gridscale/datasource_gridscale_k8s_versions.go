@@ -0,0 +1,210 @@
+package gridscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGridscaleK8sVersions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGridscaleK8sVersionsRead,
+		Schema: map[string]*schema.Schema{
+			"versions": {
+				Type:        schema.TypeList,
+				Description: "All GSK versions, sorted ascending.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"gsk_version": {
+							Type:        schema.TypeString,
+							Description: "The GSK version.",
+							Computed:    true,
+						},
+						"release": {
+							Type:        schema.TypeString,
+							Description: "The k8s release of the GSK version.",
+							Computed:    true,
+						},
+						"service_template_uuid": {
+							Type:        schema.TypeString,
+							Description: "The UUID of the PaaS service template of the GSK version.",
+							Computed:    true,
+						},
+						"active": {
+							Type:        schema.TypeBool,
+							Description: "Whether new clusters can be created with the GSK version or clusters can be upgraded to it.",
+							Computed:    true,
+						},
+						"allowed_upgrades": {
+							Type:        schema.TypeList,
+							Description: "The active GSK versions a cluster of this GSK version can be upgraded to.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"releases": {
+				Type:        schema.TypeList,
+				Description: "All k8s releases, sorted ascending.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"release": {
+							Type:        schema.TypeString,
+							Description: "The k8s release.",
+							Computed:    true,
+						},
+						"active": {
+							Type:        schema.TypeBool,
+							Description: "Whether the release has an active GSK version.",
+							Computed:    true,
+						},
+						"latest_gsk_version": {
+							Type:        schema.TypeString,
+							Description: "The latest GSK version (patch) of the release. Active GSK versions take precedence over deprecated ones.",
+							Computed:    true,
+						},
+						"latest_service_template_uuid": {
+							Type:        schema.TypeString,
+							Description: "The UUID of the PaaS service template of the latest GSK version of the release.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"active_releases": {
+				Type:        schema.TypeList,
+				Description: "The k8s releases which have an active GSK version, sorted ascending.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"deprecated_releases": {
+				Type:        schema.TypeList,
+				Description: "The k8s releases which have no active GSK version anymore, sorted ascending.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceGridscaleK8sVersionsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := "read k8s versions datasource -"
+
+	paasTemplates, err := client.GetPaaSTemplateList(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	var templates []gsclient.PaaSTemplate
+	for _, paasTemplate := range paasTemplates {
+		if paasTemplate.Properties.Flavour == k8sTemplateFlavourName {
+			templates = append(templates, paasTemplate)
+		}
+	}
+	sortK8sTemplatesByVersion(templates)
+
+	templatesByUUID := make(map[string]gsclient.PaaSTemplate)
+	for _, template := range templates {
+		templatesByUUID[template.Properties.ObjectUUID] = template
+	}
+	var ids []string
+	versions := make([]interface{}, 0)
+	for _, template := range templates {
+		props := template.Properties
+		ids = append(ids, props.ObjectUUID)
+		allowedUpgrades := make([]string, 0)
+		added := make(map[string]bool)
+		for _, uuid := range append(append([]string{}, props.PatchUpdates...), props.VersionUpgrades...) {
+			target, ok := templatesByUUID[uuid]
+			if !ok || !target.Properties.Active || added[uuid] {
+				continue
+			}
+			if checkK8sUpgradePath(template, target) == nil {
+				allowedUpgrades = append(allowedUpgrades, target.Properties.Version)
+				added[uuid] = true
+			}
+		}
+		versions = append(versions, map[string]interface{}{
+			"gsk_version":           props.Version,
+			"release":               props.Release,
+			"service_template_uuid": props.ObjectUUID,
+			"active":                props.Active,
+			"allowed_upgrades":      allowedUpgrades,
+		})
+	}
+	if err = d.Set("versions", versions); err != nil {
+		return fmt.Errorf("%s error setting versions: %v", errorPrefix, err)
+	}
+
+	// The templates are sorted ascending, so the latest GSK version of a release is the last one,
+	// active GSK versions take precedence over deprecated ones
+	latest := make(map[string]gsclient.PaaSTemplate)
+	var releaseNames []string
+	for _, template := range templates {
+		props := template.Properties
+		current, ok := latest[props.Release]
+		if !ok {
+			releaseNames = append(releaseNames, props.Release)
+		}
+		if !ok || props.Active || !current.Properties.Active {
+			latest[props.Release] = template
+		}
+	}
+	sort.SliceStable(releaseNames, func(i, j int) bool {
+		return compareK8sVersions(releaseNames[i], releaseNames[j]) < 0
+	})
+	releases := make([]interface{}, 0)
+	activeReleases := make([]string, 0)
+	deprecatedReleases := make([]string, 0)
+	for _, name := range releaseNames {
+		props := latest[name].Properties
+		releases = append(releases, map[string]interface{}{
+			"release":                      name,
+			"active":                       props.Active,
+			"latest_gsk_version":           props.Version,
+			"latest_service_template_uuid": props.ObjectUUID,
+		})
+		if props.Active {
+			activeReleases = append(activeReleases, name)
+		} else {
+			deprecatedReleases = append(deprecatedReleases, name)
+		}
+	}
+	if err = d.Set("releases", releases); err != nil {
+		return fmt.Errorf("%s error setting releases: %v", errorPrefix, err)
+	}
+	if err = d.Set("active_releases", activeReleases); err != nil {
+		return fmt.Errorf("%s error setting active_releases: %v", errorPrefix, err)
+	}
+	if err = d.Set("deprecated_releases", deprecatedReleases); err != nil {
+		return fmt.Errorf("%s error setting deprecated_releases: %v", errorPrefix, err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(ids, ",")))))
+	return nil
+}
+
+// sortK8sTemplatesByVersion sorts k8s templates ascending by their GSK version
+func sortK8sTemplatesByVersion(templates []gsclient.PaaSTemplate) {
+	sort.SliceStable(templates, func(i, j int) bool {
+		return compareK8sVersions(templates[i].Properties.Version, templates[j].Properties.Version) < 0
+	})
+}
+
+// compareK8sVersions compares two releases or GSK versions semantically. Versions which
+// cannot be parsed are compared as strings.
+func compareK8sVersions(a, b string) int {
+	versionA, errA := NewRelease(a)
+	versionB, errB := NewRelease(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return versionA.Compare(&versionB.Version)
+}
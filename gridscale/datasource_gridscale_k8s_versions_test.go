@@ -0,0 +1,68 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestCheckK8sUpgradePath(t *testing.T) {
+	template := func(uuid, release, version string) gsclient.PaaSTemplate {
+		return gsclient.PaaSTemplate{Properties: gsclient.PaaSTemplateProperties{
+			ObjectUUID: uuid,
+			Release:    release,
+			Version:    version,
+		}}
+	}
+	tests := []struct {
+		name    string
+		current gsclient.PaaSTemplate
+		target  gsclient.PaaSTemplate
+		valid   bool
+	}{
+		{"same template", template("a", "1.30", "1.30.4-gs0"), template("a", "1.30", "1.30.4-gs0"), true},
+		{"patch update", template("a", "1.30", "1.30.4-gs0"), template("b", "1.30", "1.30.5-gs0"), true},
+		{"next minor release", template("a", "1.30", "1.30.4-gs0"), template("b", "1.31", "1.31.1-gs0"), true},
+		{"skipped minor release", template("a", "1.30", "1.30.4-gs0"), template("b", "1.32", "1.32.0-gs0"), false},
+		{"release downgrade", template("a", "1.31", "1.31.1-gs0"), template("b", "1.30", "1.30.5-gs0"), false},
+		{"patch downgrade", template("a", "1.30", "1.30.5-gs0"), template("b", "1.30", "1.30.4-gs0"), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkK8sUpgradePath(test.current, test.target)
+			if test.valid && err != nil {
+				t.Errorf("expected the upgrade to be allowed, got: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected the upgrade to be rejected")
+			}
+		})
+	}
+}
+
+func TestAccDataSourceGridscaleK8sVersionsBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceGridscaleK8sVersionsConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_versions.test", "versions.0.gsk_version"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_versions.test", "releases.0.latest_gsk_version"),
+					resource.TestCheckResourceAttrSet(
+						"data.gridscale_k8s_versions.test", "active_releases.0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceGridscaleK8sVersionsConfigBasic() string {
+	return `
+data "gridscale_k8s_versions" "test" {
+}`
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func dataSourceGridscalePaaS() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Read: dataSourceGridscalePaaSRead,
 
 		Schema: map[string]*schema.Schema{
@@ -145,6 +145,8 @@ func dataSourceGridscalePaaS() *schema.Resource {
 			},
 		},
 	}
+	addPaaSConnectionSchema(r.Schema)
+	return r
 }
 
 func dataSourceGridscalePaaSRead(d *schema.ResourceData, meta interface{}) error {
@@ -213,6 +215,9 @@ func dataSourceGridscalePaaSRead(d *schema.ResourceData, meta interface{}) error
 	if err = d.Set("listen_port", listenPorts); err != nil {
 		return fmt.Errorf("%s error setting listen ports: %v", errorPrefix, err)
 	}
+	if err = setPaaSConnection(d, props, errorPrefix); err != nil {
+		return err
+	}
 
 	//Get parameters
 	parameters := make([]interface{}, 0)
@@ -0,0 +1,47 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccdataSourceGridscaleMariaDBBasic(t *testing.T) {
+	name := fmt.Sprintf("mariadb-%s", acctest.RandString(10))
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceGridscalePaaSDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceMariaDBConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_mariadb.foo", "name", name),
+					resource.TestCheckResourceAttr("data.gridscale_mariadb.foo", "release", "10.5"),
+					resource.TestCheckResourceAttr("data.gridscale_mariadb.foo", "performance_class", "standard"),
+					resource.TestCheckResourceAttr("data.gridscale_mariadb.foo", "mariadb_server_id", "2"),
+					resource.TestCheckResourceAttrPair("data.gridscale_mariadb.foo", "service_template_uuid", "gridscale_mariadb.foo", "service_template_uuid"),
+					resource.TestCheckResourceAttrPair("data.gridscale_mariadb.foo", "connection_uri", "gridscale_mariadb.foo", "connection_uri"),
+					resource.TestCheckResourceAttrSet("gridscale_mariadb.foo", "host"),
+					resource.TestCheckResourceAttrSet("gridscale_mariadb.foo", "port"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceMariaDBConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_mariadb" "foo" {
+  name = "%s"
+  release = "10.5"
+  performance_class = "standard"
+  mariadb_server_id = 2
+}
+
+data "gridscale_mariadb" "foo" {
+  resource_id = gridscale_mariadb.foo.id
+}`, name)
+}
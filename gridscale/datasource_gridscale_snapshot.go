@@ -146,8 +146,8 @@ func dataSourceGridscaleSnapshotRead(d *schema.ResourceData, meta interface{}) e
 	if err = d.Set("capacity", props.Capacity); err != nil {
 		return fmt.Errorf("%s error setting capacity: %v", errorPrefix, err)
 	}
-	//Set labels
-	if err = d.Set("labels", props.Labels); err != nil {
+	//Set labels, the reserved label of gridscale_snapshot resources is not shown
+	if err = d.Set("labels", removeSnapshotTerraformLabel(props.Labels)); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
 
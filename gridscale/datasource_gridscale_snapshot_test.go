@@ -21,6 +21,7 @@ func TestAccdataSourceGridscaleSnapshotBasic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.gridscale_snapshot.foo", "id"),
 					resource.TestCheckResourceAttr("data.gridscale_snapshot.foo", "name", name),
+					resource.TestCheckResourceAttr("data.gridscale_snapshot.foo", "labels.#", "0"),
 				),
 			},
 		},
@@ -0,0 +1,192 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGridscaleStorageBackup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGridscaleStorageBackupRead,
+		Schema: map[string]*schema.Schema{
+			"storage_uuid": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "UUID of the storage the backup belongs to.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"created_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only backups created before this time are selected (RFC3339, e.g. \"2026-10-01T02:00:00Z\", or \"2026-10-01 02:00:00\" in UTC).",
+				ValidateFunc: validateBackupTimestamp,
+			},
+			"created_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only backups created after this time are selected (RFC3339, e.g. \"2026-10-01T02:00:00Z\", or \"2026-10-01 02:00:00\" in UTC).",
+				ValidateFunc: validateBackupTimestamp,
+			},
+			"backup_schedule_uuid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only backups taken by this backup schedule of the storage are selected.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only backups whose name matches this regular expression are selected.",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If more than one backup is selected, use the most recent one. Otherwise, selecting more than one backup is an error.",
+			},
+			"storage_backup_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the selected backup. It can be used as `storage_backup_id` of a `gridscale_storage_import` or as `rollback_from_backup_uuid` of a `gridscale_storage`.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the backup.",
+			},
+			"capacity": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the backup in GB.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time (RFC3339) the backup was created.",
+			},
+			"create_time_unix": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Time the backup was created as Unix timestamp in seconds.",
+			},
+		},
+	}
+}
+
+// storageBackupFilter selects backups of a storage
+type storageBackupFilter struct {
+	createdBefore time.Time
+	createdAfter  time.Time
+	// backupUUIDs restricts the selection to these backups, if it is not nil
+	backupUUIDs map[string]bool
+	nameRegex   *regexp.Regexp
+}
+
+// parseBackupTimestamp parses a timestamp in RFC3339 or in the time layout of the provider (UTC)
+func parseBackupTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(timeLayout, value)
+}
+
+// validateBackupTimestamp validates a timestamp in RFC3339 or in the time layout of the provider
+func validateBackupTimestamp(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := parseBackupTimestamp(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s is not a valid timestamp, it has to be in RFC3339 (e.g. \"2026-10-01T02:00:00Z\") or %q format", k, timeLayout))
+	}
+	return
+}
+
+// selectStorageBackups returns the backups matching the filter, the most recent backup first
+func selectStorageBackups(backups []gsclient.StorageBackup, filter storageBackupFilter) []gsclient.StorageBackup {
+	selected := make([]gsclient.StorageBackup, 0)
+	for _, backup := range backups {
+		props := backup.Properties
+		if !filter.createdBefore.IsZero() && !props.CreateTime.Before(filter.createdBefore) {
+			continue
+		}
+		if !filter.createdAfter.IsZero() && !props.CreateTime.After(filter.createdAfter) {
+			continue
+		}
+		if filter.backupUUIDs != nil && !filter.backupUUIDs[props.ObjectUUID] {
+			continue
+		}
+		if filter.nameRegex != nil && !filter.nameRegex.MatchString(props.Name) {
+			continue
+		}
+		selected = append(selected, backup)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Properties.CreateTime.After(selected[j].Properties.CreateTime.Time)
+	})
+	return selected
+}
+
+func dataSourceGridscaleStorageBackupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	storageUUID := d.Get("storage_uuid").(string)
+	errorPrefix := fmt.Sprintf("read storage backup datasource of storage (%s) -", storageUUID)
+
+	var filter storageBackupFilter
+	if createdBefore, ok := d.GetOk("created_before"); ok {
+		filter.createdBefore, _ = parseBackupTimestamp(createdBefore.(string))
+	}
+	if createdAfter, ok := d.GetOk("created_after"); ok {
+		filter.createdAfter, _ = parseBackupTimestamp(createdAfter.(string))
+	}
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		filter.nameRegex = regexp.MustCompile(nameRegex.(string))
+	}
+	ctx := context.Background()
+	// The schedule lists the backups it took
+	if scheduleUUID, ok := d.GetOk("backup_schedule_uuid"); ok {
+		schedule, err := client.GetStorageBackupSchedule(ctx, storageUUID, scheduleUUID.(string))
+		if err != nil {
+			return fmt.Errorf("%s error getting backup schedule %s: %v", errorPrefix, scheduleUUID, err)
+		}
+		filter.backupUUIDs = make(map[string]bool)
+		for _, backup := range schedule.Properties.Relations.StorageBackups {
+			filter.backupUUIDs[backup.ObjectUUID] = true
+		}
+	}
+
+	backups, err := client.GetStorageBackupList(ctx, storageUUID)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	selected := selectStorageBackups(backups, filter)
+	if len(selected) == 0 {
+		return fmt.Errorf("%s no backup matches the selection", errorPrefix)
+	}
+	if len(selected) > 1 && !d.Get("most_recent").(bool) {
+		return fmt.Errorf("%s %d backups match the selection, narrow it down or set most_recent to true", errorPrefix, len(selected))
+	}
+
+	props := selected[0].Properties
+	d.SetId(props.ObjectUUID)
+	if err = d.Set("storage_backup_id", props.ObjectUUID); err != nil {
+		return fmt.Errorf("%s error setting storage_backup_id: %v", errorPrefix, err)
+	}
+	if err = d.Set("name", props.Name); err != nil {
+		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
+	}
+	if err = d.Set("capacity", props.Capacity); err != nil {
+		return fmt.Errorf("%s error setting capacity: %v", errorPrefix, err)
+	}
+	if err = d.Set("create_time", props.CreateTime.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("%s error setting create_time: %v", errorPrefix, err)
+	}
+	if err = d.Set("create_time_unix", int(props.CreateTime.Unix())); err != nil {
+		return fmt.Errorf("%s error setting create_time_unix: %v", errorPrefix, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package gridscale
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestSelectStorageBackups(t *testing.T) {
+	backup := func(uuid, name, createTime string) gsclient.StorageBackup {
+		created, _ := time.Parse(time.RFC3339, createTime)
+		return gsclient.StorageBackup{Properties: gsclient.StorageBackupProperties{
+			ObjectUUID: uuid,
+			Name:       name,
+			CreateTime: gsclient.GSTime{Time: created},
+		}}
+	}
+	backups := []gsclient.StorageBackup{
+		backup("1", "nightly_1", "2026-09-29T01:00:00Z"),
+		backup("3", "nightly_3", "2026-10-01T01:00:00Z"),
+		backup("2", "weekly_2", "2026-09-30T03:00:00Z"),
+		backup("4", "nightly_4", "2026-10-02T01:00:00Z"),
+		// taken by another schedule whose name shares the prefix
+		backup("5", "nightly-long_5", "2026-09-30T01:00:00Z"),
+	}
+	before, _ := parseBackupTimestamp("2026-10-01 02:00:00")
+	after, _ := parseBackupTimestamp("2026-09-29T12:00:00Z")
+	type testCase struct {
+		Filter   storageBackupFilter
+		Expected []string
+	}
+	testCases := []testCase{
+		{
+			Filter:   storageBackupFilter{},
+			Expected: []string{"4", "3", "2", "5", "1"},
+		},
+		{
+			Filter:   storageBackupFilter{createdBefore: before},
+			Expected: []string{"3", "2", "5", "1"},
+		},
+		{
+			Filter:   storageBackupFilter{createdBefore: before, createdAfter: after},
+			Expected: []string{"3", "2", "5"},
+		},
+		{
+			// the backups of a schedule are selected by the UUIDs listed in the schedule
+			Filter:   storageBackupFilter{createdBefore: before, backupUUIDs: map[string]bool{"1": true, "3": true, "4": true}},
+			Expected: []string{"3", "1"},
+		},
+		{
+			Filter:   storageBackupFilter{nameRegex: regexp.MustCompile("^weekly_")},
+			Expected: []string{"2"},
+		},
+		{
+			Filter:   storageBackupFilter{createdAfter: before, backupUUIDs: map[string]bool{"2": true}},
+			Expected: []string{},
+		},
+	}
+	for i, test := range testCases {
+		uuids := make([]string, 0)
+		for _, selected := range selectStorageBackups(backups, test.Filter) {
+			uuids = append(uuids, selected.Properties.ObjectUUID)
+		}
+		if !reflect.DeepEqual(uuids, test.Expected) {
+			t.Errorf("test case %d: expected backups %v, got %v", i, test.Expected, uuids)
+		}
+	}
+}
+
+func TestAccdataSourceGridscaleStorageBackupNoBackup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckDataSourceStorageBackupConfigBasic(),
+				ExpectError: regexp.MustCompile("no backup matches the selection"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceStorageBackupConfigBasic() string {
+	return `
+resource "gridscale_storage" "foo" {
+	name   = "storage"
+	capacity = 1
+}
+data "gridscale_storage_backup" "foo" {
+	storage_uuid = gridscale_storage.foo.id
+	created_before = "2026-10-01 02:00:00"
+	most_recent = true
+}`
+}
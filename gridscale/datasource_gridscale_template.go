@@ -12,15 +12,23 @@ import (
 )
 
 func dataSourceGridscaleTemplate() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Read: dataSourceGridscaleTemplateRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "name of the domain",
+				Optional:     true,
+				Computed:     true,
+				Description:  "Exact name of the template.",
 				ValidateFunc: validation.NoZeroValues,
+				AtLeastOneOf: templateFilterKeys,
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If more than one template is selected, use the most recently created one. Otherwise, selecting more than one template is an error.",
 			},
 			"location_uuid": {
 				Type:        schema.TypeString,
@@ -47,20 +55,10 @@ func dataSourceGridscaleTemplate() *schema.Resource {
 				Description: "Status indicates the status of the object",
 				Computed:    true,
 			},
-			"ostype": {
-				Type:        schema.TypeString,
-				Description: "The operating system installed in the template",
-				Computed:    true,
-			},
 			"version": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			"private": {
-				Type:        schema.TypeBool,
-				Description: "The object is private, the value will be true. Otherwise the value will be false.",
-				Computed:    true,
-			},
 			"license_product_no": {
 				Type:        schema.TypeInt,
 				Description: "If a template has been used that requires a license key (e.g. Windows Servers) this shows the product_no of the license (see the /prices endpoint for more details).",
@@ -76,22 +74,11 @@ func dataSourceGridscaleTemplate() *schema.Resource {
 				Description: "The date and time of the last object change.",
 				Computed:    true,
 			},
-			"distro": {
-				Type:        schema.TypeString,
-				Description: "The OS distribution that the template contains.",
-				Computed:    true,
-			},
 			"description": {
 				Type:        schema.TypeString,
 				Description: "Description of the template.",
 				Computed:    true,
 			},
-			"labels": {
-				Type:        schema.TypeSet,
-				Description: "List of labels.",
-				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
 			"usage_in_minutes": {
 				Type:        schema.TypeInt,
 				Description: "Total minutes the object has been running.",
@@ -109,20 +96,35 @@ func dataSourceGridscaleTemplate() *schema.Resource {
 			},
 		},
 	}
+	addTemplateFilterSchema(r.Schema)
+	return r
 }
 
 func dataSourceGridscaleTemplateRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*gsclient.Client)
 
-	name := d.Get("name").(string)
-	errorPrefix := fmt.Sprintf("read template (%s) datasource -", name)
+	errorPrefix := "read template datasource -"
+	if name := d.Get("name").(string); name != "" {
+		errorPrefix = fmt.Sprintf("read template (%s) datasource -", name)
+	}
 
-	template, err := client.GetTemplateByName(context.Background(), name)
+	filter, err := expandTemplateFilter(d)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	templates, err := client.GetTemplateList(context.Background())
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
+	template, err := selectTemplate(templates, filter, d.Get("most_recent").(bool))
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
 
 	d.SetId(template.Properties.ObjectUUID)
+	if err = d.Set("name", template.Properties.Name); err != nil {
+		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
+	}
 	if err = d.Set("location_uuid", template.Properties.LocationUUID); err != nil {
 		return fmt.Errorf("%s error setting location_uuid: %v", errorPrefix, err)
 	}
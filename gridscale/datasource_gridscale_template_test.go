@@ -2,6 +2,7 @@ package gridscale
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -22,6 +23,14 @@ func TestAccDataSourceTemplateBasic(t *testing.T) {
 					resource.TestCheckResourceAttrSet("data.gridscale_template.foo", "id"),
 				),
 			},
+			{
+				Config: testAccCheckDataSourceGridscaleTemplateConfigFilter(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_template.foo", "id"),
+					resource.TestCheckResourceAttr("data.gridscale_template.foo", "distro", "ubuntu"),
+					resource.TestMatchResourceAttr("data.gridscale_template.foo", "name", regexp.MustCompile("^Ubuntu")),
+				),
+			},
 		},
 	})
 
@@ -34,3 +43,14 @@ data "gridscale_template" "foo" {
 }
 `, name)
 }
+
+func testAccCheckDataSourceGridscaleTemplateConfigFilter() string {
+	return `
+data "gridscale_template" "foo" {
+	distro             = "ubuntu"
+	version_constraint = ">= 22.04"
+	private            = false
+	most_recent        = true
+}
+`
+}
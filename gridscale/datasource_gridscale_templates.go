@@ -0,0 +1,129 @@
+package gridscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGridscaleTemplates() *schema.Resource {
+	r := &schema.Resource{
+		Read: dataSourceGridscaleTemplatesRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only templates with exactly this name are selected.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"templates": {
+				Type:        schema.TypeList,
+				Description: "The selected templates, the most recently created first.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_uuid": {
+							Type:        schema.TypeString,
+							Description: "UUID of the template.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the template.",
+							Computed:    true,
+						},
+						"distro": {
+							Type:        schema.TypeString,
+							Description: "The OS distribution that the template contains.",
+							Computed:    true,
+						},
+						"ostype": {
+							Type:        schema.TypeString,
+							Description: "The operating system installed in the template.",
+							Computed:    true,
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Description: "The version of the template.",
+							Computed:    true,
+						},
+						"private": {
+							Type:        schema.TypeBool,
+							Description: "Whether the template is private.",
+							Computed:    true,
+						},
+						"labels": {
+							Type:        schema.TypeList,
+							Description: "List of labels.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"capacity": {
+							Type:        schema.TypeInt,
+							Description: "The capacity of the template in GB.",
+							Computed:    true,
+						},
+						"create_time": {
+							Type:        schema.TypeString,
+							Description: "The date and time the template was created.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"template_uuids": {
+				Type:        schema.TypeList,
+				Description: "UUIDs of the selected templates, the most recently created first.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+	addTemplateFilterSchema(r.Schema)
+	return r
+}
+
+func dataSourceGridscaleTemplatesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := "read templates datasource -"
+
+	filter, err := expandTemplateFilter(d)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	templates, err := client.GetTemplateList(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	selected := selectTemplates(templates, filter)
+	templateList := make([]interface{}, 0, len(selected))
+	templateUUIDs := make([]string, 0, len(selected))
+	for _, template := range selected {
+		props := template.Properties
+		templateList = append(templateList, map[string]interface{}{
+			"object_uuid": props.ObjectUUID,
+			"name":        props.Name,
+			"distro":      props.Distro,
+			"ostype":      props.Ostype,
+			"version":     props.Version,
+			"private":     props.Private,
+			"labels":      props.Labels,
+			"capacity":    props.Capacity,
+			"create_time": props.CreateTime.String(),
+		})
+		templateUUIDs = append(templateUUIDs, props.ObjectUUID)
+	}
+	if err = d.Set("templates", templateList); err != nil {
+		return fmt.Errorf("%s error setting templates: %v", errorPrefix, err)
+	}
+	if err = d.Set("template_uuids", templateUUIDs); err != nil {
+		return fmt.Errorf("%s error setting template_uuids: %v", errorPrefix, err)
+	}
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(templateUUIDs, ",")))))
+	return nil
+}
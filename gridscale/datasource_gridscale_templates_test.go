@@ -0,0 +1,35 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTemplatesBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceGridscaleTemplatesConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_templates.foo", "id"),
+					resource.TestCheckResourceAttrSet("data.gridscale_templates.foo", "template_uuids.0"),
+					resource.TestCheckResourceAttr("data.gridscale_templates.foo", "templates.0.distro", "ubuntu"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceGridscaleTemplatesConfigBasic() string {
+	return `
+data "gridscale_templates" "foo" {
+	distro     = "ubuntu"
+	name_regex = "LTS"
+}
+`
+}
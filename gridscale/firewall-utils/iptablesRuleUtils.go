@@ -0,0 +1,264 @@
+package fwu
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+// supportedIptablesMatches are the match modules (-m) which can be translated
+var supportedIptablesMatches = map[string]bool{
+	"tcp":       true,
+	"udp":       true,
+	"multiport": true,
+	"comment":   true,
+}
+
+// ParseIptablesSave translates the INPUT and OUTPUT chains of the filter table
+// of an iptables-save (forIPv6 = false) or ip6tables-save (forIPv6 = true) dump
+// to gridscale firewall rules. Rules which cannot be represented are skipped
+// and reported as diagnostics.
+func ParseIptablesSave(text string, forIPv6 bool) (HostRules, []RuleDiagnostic, error) {
+	var result HostRules
+	var diags []RuleDiagnostic
+	table := ""
+	foundFilterTable := false
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+			if table == "filter" {
+				foundFilterTable = true
+			}
+		case line == "COMMIT":
+			table = ""
+		case table != "filter":
+			// nat, mangle, raw, ... tables have no equivalent in gridscale firewalls
+			continue
+		case strings.HasPrefix(line, ":"):
+			if diag, ok := checkIptablesChainPolicy(line, lineNum); ok {
+				diags = append(diags, diag)
+			}
+		case strings.HasPrefix(line, "-A ") || strings.HasPrefix(line, "--append "):
+			rule, diag, err := parseIptablesRule(line, lineNum)
+			if err != nil {
+				return HostRules{}, nil, fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			if diag != nil {
+				diags = append(diags, *diag)
+				if diag.Skipped {
+					continue
+				}
+			}
+			rule.forIPv4 = !forIPv6
+			rule.forIPv6 = forIPv6
+			rule.addTo(&result)
+		default:
+			return HostRules{}, nil, fmt.Errorf("line %d: unexpected content %q, expected iptables-save output", lineNum, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return HostRules{}, nil, err
+	}
+	if !foundFilterTable {
+		return HostRules{}, nil, fmt.Errorf("no *filter table found, expected iptables-save output")
+	}
+	return result, diags, nil
+}
+
+// checkIptablesChainPolicy reports chain policies which differ from the
+// behavior of gridscale firewalls (inbound: drop, outbound: accept).
+func checkIptablesChainPolicy(line string, lineNum int) (RuleDiagnostic, bool) {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) < 2 {
+		return RuleDiagnostic{}, false
+	}
+	chain, policy := fields[0], fields[1]
+	if (chain == "INPUT" && policy == "ACCEPT") || (chain == "OUTPUT" && policy == "DROP") {
+		return RuleDiagnostic{
+			Line:    lineNum,
+			Rule:    line,
+			Message: fmt.Sprintf("policy %s of chain %s cannot be represented, gridscale firewalls drop unmatched inbound and accept unmatched outbound packets", policy, chain),
+		}, true
+	}
+	return RuleDiagnostic{}, false
+}
+
+// parseIptablesRule parses a single "-A CHAIN ..." line. A non-nil diagnostic
+// is returned for rules which are skipped or only approximated, an error is
+// returned for lines which are not valid iptables-save output.
+func parseIptablesRule(line string, lineNum int) (hostRule, *RuleDiagnostic, error) {
+	fields, err := splitRuleFields(line)
+	if err != nil {
+		return hostRule{}, nil, err
+	}
+	skip := func(format string, a ...interface{}) (hostRule, *RuleDiagnostic, error) {
+		return hostRule{}, &RuleDiagnostic{Line: lineNum, Rule: line, Message: fmt.Sprintf(format, a...), Skipped: true}, nil
+	}
+	if len(fields) < 2 {
+		return hostRule{}, nil, fmt.Errorf("missing chain name in %q", line)
+	}
+	var rule hostRule
+	switch fields[1] {
+	case "INPUT":
+		rule.direction = chainDirectionIn
+	case "OUTPUT":
+		rule.direction = chainDirectionOut
+	default:
+		return skip("chain %s has no equivalent in gridscale firewalls, only INPUT and OUTPUT are supported", fields[1])
+	}
+	var note string
+	for i := 2; i < len(fields); i++ {
+		opt := fields[i]
+		if opt == "!" {
+			return skip("negated matches are not supported")
+		}
+		// options without value
+		if opt == "-f" || opt == "--fragment" {
+			return skip("option %s is not supported", opt)
+		}
+		if i+1 >= len(fields) {
+			return hostRule{}, nil, fmt.Errorf("option %s requires a value", opt)
+		}
+		i++
+		val := fields[i]
+		switch opt {
+		case "-s", "--source", "--src":
+			rule.srcCidrs = strings.Split(val, ",")
+		case "-d", "--destination", "--dst":
+			rule.dstCidrs = strings.Split(val, ",")
+		case "-p", "--protocol":
+			protocol, err := parseProtocol(val)
+			if err != nil {
+				return skip("%v", err)
+			}
+			rule.protocols = []gsclient.TransportLayerProtocol{protocol}
+		case "-m", "--match":
+			if !supportedIptablesMatches[val] {
+				return skip("match module %q is not supported", val)
+			}
+		case "--dport", "--destination-port", "--dports", "--destination-ports":
+			ports, err := parseIptablesPorts(val)
+			if err != nil {
+				return skip("%v", err)
+			}
+			rule.dstPorts = ports
+		case "--sport", "--source-port", "--sports", "--source-ports":
+			ports, err := parseIptablesPorts(val)
+			if err != nil {
+				return skip("%v", err)
+			}
+			rule.srcPorts = ports
+		case "--comment":
+			rule.comment = val
+		case "-j", "--jump":
+			switch val {
+			case "ACCEPT":
+				rule.action = "accept"
+			case "DROP":
+				rule.action = "drop"
+			case "REJECT":
+				rule.action = "drop"
+				note = "target REJECT was translated to drop"
+			default:
+				return skip("target %s is not supported, only ACCEPT, DROP and REJECT can be translated", val)
+			}
+		case "--reject-with":
+			// only relevant for REJECT, which is translated to drop
+		default:
+			return skip("option %s is not supported", opt)
+		}
+	}
+	if rule.action == "" {
+		return skip("rules without ACCEPT, DROP or REJECT target are not supported")
+	}
+	if (len(rule.srcPorts) > 0 || len(rule.dstPorts) > 0) && len(rule.protocols) == 0 {
+		return hostRule{}, nil, fmt.Errorf("port matches require a protocol (-p tcp or -p udp) in %q", line)
+	}
+	if note != "" {
+		return rule, &RuleDiagnostic{Line: lineNum, Rule: line, Message: note}, nil
+	}
+	return rule, nil, nil
+}
+
+// parseIptablesPorts parses a port, a port range or a multiport list
+func parseIptablesPorts(val string) ([]string, error) {
+	var ports []string
+	for _, port := range strings.Split(val, ",") {
+		normalized, err := normalizePort(port, ":")
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, normalized)
+	}
+	return ports, nil
+}
+
+// ExportIptablesSave renders inbound and outbound firewall rules as the
+// filter table of an iptables-save dump. The result can be parsed again
+// with ParseIptablesSave.
+func ExportIptablesSave(rulesIn, rulesOut []gsclient.FirewallRuleProperties) string {
+	var sb strings.Builder
+	inPolicy := "ACCEPT"
+	if len(rulesIn) > 0 {
+		inPolicy = "DROP"
+	}
+	sb.WriteString("*filter\n")
+	fmt.Fprintf(&sb, ":INPUT %s [0:0]\n", inPolicy)
+	sb.WriteString(":FORWARD ACCEPT [0:0]\n")
+	sb.WriteString(":OUTPUT ACCEPT [0:0]\n")
+	for _, rule := range sortRulesByOrder(rulesIn) {
+		sb.WriteString(formatIptablesRule("INPUT", rule))
+	}
+	for _, rule := range sortRulesByOrder(rulesOut) {
+		sb.WriteString(formatIptablesRule("OUTPUT", rule))
+	}
+	sb.WriteString("COMMIT\n")
+	return sb.String()
+}
+
+func sortRulesByOrder(rules []gsclient.FirewallRuleProperties) []gsclient.FirewallRuleProperties {
+	sorted := make([]gsclient.FirewallRuleProperties, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Order < sorted[j].Order
+	})
+	return sorted
+}
+
+func formatIptablesRule(chain string, rule gsclient.FirewallRuleProperties) string {
+	parts := []string{"-A", chain}
+	if rule.SrcCidr != "" {
+		parts = append(parts, "-s", rule.SrcCidr)
+	}
+	if rule.DstCidr != "" {
+		parts = append(parts, "-d", rule.DstCidr)
+	}
+	protocol := string(rule.Protocol)
+	parts = append(parts, "-p", protocol, "-m", protocol)
+	if rule.SrcPort != "" {
+		parts = append(parts, "--sport", rule.SrcPort)
+	}
+	if rule.DstPort != "" {
+		parts = append(parts, "--dport", rule.DstPort)
+	}
+	if rule.Comment != "" {
+		comment := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(rule.Comment)
+		parts = append(parts, "-m", "comment", "--comment", `"`+comment+`"`)
+	}
+	target := "DROP"
+	if rule.Action == "accept" {
+		target = "ACCEPT"
+	}
+	parts = append(parts, "-j", target)
+	return strings.Join(parts, " ") + "\n"
+}
@@ -0,0 +1,339 @@
+package fwu
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+// nftChain holds the state of the chain which is currently parsed
+type nftChain struct {
+	name      string
+	chainType string
+	hook      string
+}
+
+// ParseNftRuleset translates the filter base chains hooked to input and output
+// of an "nft list ruleset" dump to gridscale firewall rules. Tables of the
+// ip family are translated to IPv4 rules, ip6 to IPv6 rules and inet to both,
+// unless a rule matches on ip or ip6 addresses. Rules which cannot be
+// represented are skipped and reported as diagnostics.
+func ParseNftRuleset(text string) (HostRules, []RuleDiagnostic, error) {
+	var result HostRules
+	var diags []RuleDiagnostic
+	var family string
+	var chain *nftChain
+	inTable := false
+	skipDepth := 0
+	foundTable := false
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(stripNftComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		// skip sets, maps, flowtables, ... and everything inside them
+		if skipDepth > 0 {
+			skipDepth += strings.Count(line, "{") - strings.Count(line, "}")
+			continue
+		}
+		switch {
+		case !inTable:
+			fields := strings.Fields(line)
+			if len(fields) != 4 || fields[0] != "table" || fields[3] != "{" {
+				return HostRules{}, nil, fmt.Errorf("line %d: unexpected content %q, expected nft list ruleset output", lineNum, line)
+			}
+			inTable = true
+			foundTable = true
+			family = fields[1]
+			if family != "ip" && family != "ip6" && family != "inet" {
+				// arp, bridge and netdev tables have no equivalent in gridscale firewalls
+				skipDepth = 1
+				inTable = false
+			}
+		case chain == nil:
+			fields := strings.Fields(line)
+			switch {
+			case line == "}":
+				inTable = false
+			case len(fields) == 3 && fields[0] == "chain" && fields[2] == "{":
+				chain = &nftChain{name: fields[1]}
+			case strings.HasSuffix(line, "{"):
+				skipDepth = 1
+			}
+		case line == "}":
+			chain = nil
+		case strings.HasPrefix(line, "type "):
+			if diag, ok := parseNftChainDefinition(chain, line, lineNum); ok {
+				diags = append(diags, diag)
+			}
+		case strings.HasPrefix(line, "policy "), strings.HasPrefix(line, "comment "), strings.HasPrefix(line, "devices "):
+			continue
+		default:
+			rule, diag, err := parseNftRule(chain, family, line, lineNum)
+			if err != nil {
+				return HostRules{}, nil, fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			if diag != nil {
+				diags = append(diags, *diag)
+				if diag.Skipped {
+					continue
+				}
+			}
+			rule.addTo(&result)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return HostRules{}, nil, err
+	}
+	if !foundTable {
+		return HostRules{}, nil, fmt.Errorf("no table found, expected nft list ruleset output")
+	}
+	if inTable || chain != nil || skipDepth > 0 {
+		return HostRules{}, nil, fmt.Errorf("unexpected end of ruleset, missing '}'")
+	}
+	return result, diags, nil
+}
+
+// stripNftComment removes "# ..." comments (e.g. "# handle 4" printed by nft -a)
+func stripNftComment(line string) string {
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '#':
+			if !inQuotes {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// parseNftChainDefinition parses "type filter hook input priority filter; policy drop;"
+// and reports policies which differ from the behavior of gridscale firewalls.
+func parseNftChainDefinition(chain *nftChain, line string, lineNum int) (RuleDiagnostic, bool) {
+	var policy string
+	for _, statement := range strings.Split(line, ";") {
+		fields := strings.Fields(statement)
+		for i := 0; i+1 < len(fields); i++ {
+			switch fields[i] {
+			case "type":
+				chain.chainType = fields[i+1]
+			case "hook":
+				chain.hook = fields[i+1]
+			case "policy":
+				policy = fields[i+1]
+			}
+		}
+	}
+	if chain.chainType != "filter" {
+		return RuleDiagnostic{}, false
+	}
+	if (chain.hook == "input" && policy == "accept") || (chain.hook == "output" && policy == "drop") {
+		return RuleDiagnostic{
+			Line:    lineNum,
+			Rule:    line,
+			Message: fmt.Sprintf("policy %s of chain %s cannot be represented, gridscale firewalls drop unmatched inbound and accept unmatched outbound packets", policy, chain.name),
+		}, true
+	}
+	return RuleDiagnostic{}, false
+}
+
+// parseNftRule parses a single rule of a chain. A non-nil diagnostic is returned
+// for rules which are skipped or only approximated, an error is returned for
+// lines which are not valid nft output.
+func parseNftRule(chain *nftChain, family, line string, lineNum int) (hostRule, *RuleDiagnostic, error) {
+	skip := func(format string, a ...interface{}) (hostRule, *RuleDiagnostic, error) {
+		return hostRule{}, &RuleDiagnostic{Line: lineNum, Rule: line, Message: fmt.Sprintf(format, a...), Skipped: true}, nil
+	}
+	if chain.chainType != "filter" || chain.hook == "" {
+		return skip("chain %s is not a filter base chain", chain.name)
+	}
+	var rule hostRule
+	switch chain.hook {
+	case "input":
+		rule.direction = chainDirectionIn
+	case "output":
+		rule.direction = chainDirectionOut
+	default:
+		return skip("hook %s of chain %s has no equivalent in gridscale firewalls, only input and output are supported", chain.hook, chain.name)
+	}
+	rule.forIPv4 = family == "ip" || family == "inet"
+	rule.forIPv6 = family == "ip6" || family == "inet"
+
+	fields, err := splitRuleFields(line)
+	if err != nil {
+		return hostRule{}, nil, err
+	}
+	// value returns the value of a match, e.g. "22" of "tcp dport 22"
+	value := func(i int) ([]string, string, bool) {
+		if i >= len(fields) {
+			return nil, "", false
+		}
+		val := fields[i]
+		if val == "!=" {
+			return nil, "negated matches are not supported", false
+		}
+		if strings.HasPrefix(val, "@") {
+			return nil, fmt.Sprintf("named set %s is not supported", val), false
+		}
+		if strings.HasPrefix(val, "{") {
+			var vals []string
+			for _, v := range strings.Split(strings.Trim(val, "{}"), ",") {
+				vals = append(vals, strings.TrimSpace(v))
+			}
+			return vals, "", true
+		}
+		return []string{val}, "", true
+	}
+	var note string
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch field {
+		case "ip", "ip6":
+			if i+1 >= len(fields) {
+				return hostRule{}, nil, fmt.Errorf("incomplete %s expression", field)
+			}
+			if (field == "ip" && !rule.forIPv4) || (field == "ip6" && !rule.forIPv6) {
+				return skip("%s expression does not match the %s table family", field, family)
+			}
+			rule.forIPv4 = field == "ip"
+			rule.forIPv6 = field == "ip6"
+			i++
+			vals, reason, ok := value(i + 1)
+			if !ok {
+				if reason == "" {
+					reason = fmt.Sprintf("incomplete %s %s expression", field, fields[i])
+				}
+				return skip("%s", reason)
+			}
+			switch fields[i] {
+			case "saddr":
+				rule.srcCidrs = vals
+			case "daddr":
+				rule.dstCidrs = vals
+			case "protocol", "nexthdr":
+				if msg := setNftProtocols(&rule, vals); msg != "" {
+					return skip("%s", msg)
+				}
+			default:
+				return skip("match %s %s is not supported", field, fields[i])
+			}
+			i++
+		case "tcp", "udp", "th":
+			if i+1 >= len(fields) {
+				return hostRule{}, nil, fmt.Errorf("incomplete %s expression", field)
+			}
+			if field != "th" {
+				if msg := setNftProtocols(&rule, []string{field}); msg != "" {
+					return skip("%s", msg)
+				}
+			}
+			i++
+			if fields[i] != "dport" && fields[i] != "sport" {
+				return skip("match %s %s is not supported", field, fields[i])
+			}
+			vals, reason, ok := value(i + 1)
+			if !ok {
+				if reason == "" {
+					reason = fmt.Sprintf("incomplete %s %s expression", field, fields[i])
+				}
+				return skip("%s", reason)
+			}
+			var ports []string
+			for _, v := range vals {
+				port, err := normalizePort(v, "-")
+				if err != nil {
+					return skip("%v", err)
+				}
+				ports = append(ports, port)
+			}
+			if fields[i] == "dport" {
+				rule.dstPorts = ports
+			} else {
+				rule.srcPorts = ports
+			}
+			i++
+		case "meta":
+			if i+1 >= len(fields) || fields[i+1] != "l4proto" {
+				return skip("meta expressions other than l4proto are not supported")
+			}
+			i++
+			vals, reason, ok := value(i + 1)
+			if !ok {
+				if reason == "" {
+					reason = "incomplete meta l4proto expression"
+				}
+				return skip("%s", reason)
+			}
+			if msg := setNftProtocols(&rule, vals); msg != "" {
+				return skip("%s", msg)
+			}
+			i++
+		case "counter":
+			// "counter packets 0 bytes 0"
+			if i+4 < len(fields) && fields[i+1] == "packets" && fields[i+3] == "bytes" {
+				i += 4
+			}
+		case "comment":
+			if i+1 >= len(fields) {
+				return hostRule{}, nil, fmt.Errorf("incomplete comment")
+			}
+			i++
+			rule.comment = fields[i]
+		case "accept":
+			rule.action = "accept"
+		case "drop":
+			rule.action = "drop"
+		case "reject":
+			rule.action = "drop"
+			note = "verdict reject was translated to drop"
+			// "reject with icmp type port-unreachable", "reject with tcp reset"
+			for i+1 < len(fields) && fields[i+1] != "comment" {
+				i++
+			}
+		default:
+			return skip("expression %q is not supported", field)
+		}
+	}
+	if rule.action == "" {
+		return skip("rules without accept, drop or reject verdict are not supported")
+	}
+	if note != "" {
+		return rule, &RuleDiagnostic{Line: lineNum, Rule: line, Message: note}, nil
+	}
+	return rule, nil, nil
+}
+
+// setNftProtocols restricts the protocols of a rule. It returns a message if
+// the protocols are not supported or conflict with a previous match.
+func setNftProtocols(rule *hostRule, vals []string) string {
+	var protocols []gsclient.TransportLayerProtocol
+	for _, v := range vals {
+		protocol, err := parseProtocol(v)
+		if err != nil {
+			return err.Error()
+		}
+		if len(rule.protocols) > 0 && !containsProtocol(rule.protocols, protocol) {
+			return fmt.Sprintf("protocol %s conflicts with a previous protocol match", protocol)
+		}
+		protocols = append(protocols, protocol)
+	}
+	rule.protocols = protocols
+	return ""
+}
+
+func containsProtocol(protocols []gsclient.TransportLayerProtocol, protocol gsclient.TransportLayerProtocol) bool {
+	for _, p := range protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package fwu
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+// RuleSet is a named, reusable fragment of firewall rules. Rule sets are
+// produced by the gridscale_firewall_rule_set data source (JSON encoded)
+// and merged into firewalls and server networks via `rule_sets`.
+type RuleSet struct {
+	Name  string                 `json:"name"`
+	Rules gsclient.FirewallRules `json:"rules"`
+}
+
+// EncodeRuleSet encodes a rule set to JSON
+func EncodeRuleSet(ruleSet RuleSet) (string, error) {
+	data, err := json.Marshal(ruleSet)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DecodeRuleSet decodes a JSON encoded rule set
+func DecodeRuleSet(str string) (RuleSet, error) {
+	var ruleSet RuleSet
+	if err := json.Unmarshal([]byte(str), &ruleSet); err != nil {
+		return RuleSet{}, fmt.Errorf("invalid rule set, use the rule_set attribute of a gridscale_firewall_rule_set data source: %v", err)
+	}
+	return ruleSet, nil
+}
+
+// DecodeRuleSets decodes a list of JSON encoded rule sets
+func DecodeRuleSets(strs []string) ([]RuleSet, error) {
+	var ruleSets []RuleSet
+	for _, str := range strs {
+		ruleSet, err := DecodeRuleSet(str)
+		if err != nil {
+			return nil, err
+		}
+		ruleSets = append(ruleSets, ruleSet)
+	}
+	return ruleSets, nil
+}
+
+// RenumberRules sorts rules by their order (rules with the same order keep
+// their sequence) and renumbers them starting from 0.
+func RenumberRules(rules []gsclient.FirewallRuleProperties) []gsclient.FirewallRuleProperties {
+	if len(rules) == 0 {
+		return nil
+	}
+	renumbered := sortRulesByOrder(rules)
+	for i := range renumbered {
+		renumbered[i].Order = i
+	}
+	return renumbered
+}
+
+// ruleMatch identifies the traffic a rule matches, regardless of its action
+type ruleMatch struct {
+	protocol gsclient.TransportLayerProtocol
+	srcCidr  string
+	dstCidr  string
+	srcPort  string
+	dstPort  string
+}
+
+func (m ruleMatch) String() string {
+	str := string(m.protocol)
+	if m.srcCidr != "" {
+		str += " from " + m.srcCidr
+	}
+	if m.srcPort != "" {
+		str += " src_port " + m.srcPort
+	}
+	if m.dstCidr != "" {
+		str += " to " + m.dstCidr
+	}
+	if m.dstPort != "" {
+		str += " dst_port " + m.dstPort
+	}
+	return str
+}
+
+func matchOf(rule gsclient.FirewallRuleProperties) ruleMatch {
+	return ruleMatch{
+		protocol: rule.Protocol,
+		srcCidr:  rule.SrcCidr,
+		dstCidr:  rule.DstCidr,
+		srcPort:  rule.SrcPort,
+		dstPort:  rule.DstPort,
+	}
+}
+
+// ruleListNames are the names of the rule lists, in the sequence of ruleLists
+var ruleListNames = []string{"rules_v4_in", "rules_v4_out", "rules_v6_in", "rules_v6_out"}
+
+// ruleLists returns pointers to the rule lists of firewall rules
+func ruleLists(rules *gsclient.FirewallRules) []*[]gsclient.FirewallRuleProperties {
+	return []*[]gsclient.FirewallRuleProperties{&rules.RulesV4In, &rules.RulesV4Out, &rules.RulesV6In, &rules.RulesV6Out}
+}
+
+// MergeRuleSets appends the rules of the rule sets, in the given sequence,
+// to the explicitly declared rules. Explicit rules keep their order, the
+// rules of the rule sets are numbered after the highest explicit order.
+// Conflicts are resolved deterministically: the first rule (explicit rules
+// first, then rule sets in sequence) matching a given traffic wins, later
+// rules matching the same traffic are skipped. Every resolved conflict is
+// returned as a message.
+func MergeRuleSets(explicit gsclient.FirewallRules, ruleSets []RuleSet) (gsclient.FirewallRules, []string) {
+	var merged gsclient.FirewallRules
+	var conflicts []string
+	explicitLists := ruleLists(&explicit)
+	for i, mergedList := range ruleLists(&merged) {
+		var listConflicts []string
+		*mergedList, _, listConflicts = mergeRuleList(i, *explicitLists[i], ruleSets)
+		conflicts = append(conflicts, listConflicts...)
+	}
+	return merged, conflicts
+}
+
+// RemoveRuleSetRules removes the rules which MergeRuleSets added for the
+// given explicit rules and rule sets from rules read from the API, so that
+// only the explicitly declared rules remain.
+func RemoveRuleSetRules(rules, explicit gsclient.FirewallRules, ruleSets []RuleSet) gsclient.FirewallRules {
+	if len(ruleSets) == 0 {
+		return rules
+	}
+	var result gsclient.FirewallRules
+	explicitLists := ruleLists(&explicit)
+	rulesLists := ruleLists(&rules)
+	for i, resultList := range ruleLists(&result) {
+		_, added, _ := mergeRuleList(i, *explicitLists[i], ruleSets)
+		*resultList = removeRules(*rulesLists[i], added)
+	}
+	return result
+}
+
+// mergeRuleList merges one rule list (index of ruleLists) of the rule sets
+// into the explicit rules. It returns the merged list, the rules added from
+// the rule sets and the resolved conflicts.
+func mergeRuleList(listIdx int, explicit []gsclient.FirewallRuleProperties, ruleSets []RuleSet) ([]gsclient.FirewallRuleProperties, []gsclient.FirewallRuleProperties, []string) {
+	type origin struct {
+		action string
+		source string
+	}
+	listName := ruleListNames[listIdx]
+	var conflicts []string
+	merged := append([]gsclient.FirewallRuleProperties(nil), explicit...)
+	seen := make(map[ruleMatch]origin)
+	usedOrders := make(map[int]bool)
+	nextOrder := 0
+	for _, rule := range explicit {
+		if usedOrders[rule.Order] {
+			conflicts = append(conflicts, fmt.Sprintf("%s: order %d is used by more than one rule", listName, rule.Order))
+		}
+		usedOrders[rule.Order] = true
+		if rule.Order >= nextOrder {
+			nextOrder = rule.Order + 1
+		}
+	}
+	// explicit rules are evaluated by their order
+	for _, rule := range sortRulesByOrder(explicit) {
+		if _, ok := seen[matchOf(rule)]; !ok {
+			seen[matchOf(rule)] = origin{action: rule.Action, source: listName}
+		}
+	}
+	var added []gsclient.FirewallRuleProperties
+	for _, ruleSet := range ruleSets {
+		ruleSetRules := ruleSet.Rules
+		for _, rule := range RenumberRules(*ruleLists(&ruleSetRules)[listIdx]) {
+			match := matchOf(rule)
+			if prev, ok := seen[match]; ok {
+				if prev.action == rule.Action {
+					conflicts = append(conflicts, fmt.Sprintf("%s: rule %q of rule set %q duplicates a rule of %s and is skipped", listName, match.String(), ruleSet.Name, prev.source))
+				} else {
+					conflicts = append(conflicts, fmt.Sprintf("%s: rule %q (%s) of rule set %q is shadowed by a %s rule of %s and is skipped", listName, match.String(), rule.Action, ruleSet.Name, prev.action, prev.source))
+				}
+				continue
+			}
+			seen[match] = origin{action: rule.Action, source: fmt.Sprintf("rule set %q", ruleSet.Name)}
+			rule.Order = nextOrder
+			nextOrder++
+			merged = append(merged, rule)
+			added = append(added, rule)
+		}
+	}
+	return merged, added, conflicts
+}
+
+// removeRules removes every rule of toRemove from rules (once per occurrence)
+func removeRules(rules, toRemove []gsclient.FirewallRuleProperties) []gsclient.FirewallRuleProperties {
+	if len(toRemove) == 0 {
+		return rules
+	}
+	remaining := make(map[gsclient.FirewallRuleProperties]int)
+	for _, rule := range toRemove {
+		remaining[rule]++
+	}
+	var result []gsclient.FirewallRuleProperties
+	for _, rule := range rules {
+		if remaining[rule] > 0 {
+			remaining[rule]--
+			continue
+		}
+		result = append(result, rule)
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package fwu
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestMergeRuleSets(t *testing.T) {
+	explicit := gsclient.FirewallRules{
+		RulesV4In: []gsclient.FirewallRuleProperties{
+			{Order: 5, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "accept"},
+		},
+	}
+	ssh := RuleSet{
+		Name: "ssh",
+		Rules: gsclient.FirewallRules{
+			RulesV4In: []gsclient.FirewallRuleProperties{
+				{Order: 1, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.0/8", DstPort: "22", Action: "accept", Comment: "ssh"},
+				{Order: 0, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.1/32", DstPort: "22", Action: "drop"},
+			},
+		},
+	}
+	web := RuleSet{
+		Name: "web",
+		Rules: gsclient.FirewallRules{
+			RulesV4In: []gsclient.FirewallRuleProperties{
+				{Order: 0, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "drop"},
+				{Order: 1, Protocol: gsclient.TCPTransport, DstPort: "443", Action: "accept"},
+			},
+			RulesV6Out: []gsclient.FirewallRuleProperties{
+				{Order: 3, Protocol: gsclient.UDPTransport, DstPort: "53", Action: "accept"},
+			},
+		},
+	}
+	expected := gsclient.FirewallRules{
+		RulesV4In: []gsclient.FirewallRuleProperties{
+			{Order: 5, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "accept"},
+			{Order: 6, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.1/32", DstPort: "22", Action: "drop"},
+			{Order: 7, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.0/8", DstPort: "22", Action: "accept", Comment: "ssh"},
+			{Order: 8, Protocol: gsclient.TCPTransport, DstPort: "443", Action: "accept"},
+		},
+		RulesV6Out: []gsclient.FirewallRuleProperties{
+			{Order: 0, Protocol: gsclient.UDPTransport, DstPort: "53", Action: "accept"},
+		},
+	}
+	merged, conflicts := MergeRuleSets(explicit, []RuleSet{ssh, web})
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected merged rules %+v, got %+v", expected, merged)
+	}
+	if len(conflicts) != 1 {
+		t.Errorf("expected 1 conflict, got %v", conflicts)
+	}
+
+	// Rules read from the API contain the merged rules, only the explicit ones have to remain
+	remaining := RemoveRuleSetRules(merged, explicit, []RuleSet{ssh, web})
+	if !reflect.DeepEqual(remaining, explicit) {
+		t.Errorf("expected remaining rules %+v, got %+v", explicit, remaining)
+	}
+}
+
+func TestRuleSetEncoding(t *testing.T) {
+	ruleSet := RuleSet{
+		Name: "ssh",
+		Rules: gsclient.FirewallRules{
+			RulesV4In: []gsclient.FirewallRuleProperties{
+				{Order: 0, Protocol: gsclient.TCPTransport, DstPort: "22", Action: "accept"},
+			},
+		},
+	}
+	encoded, err := EncodeRuleSet(ruleSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := DecodeRuleSet(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, ruleSet) {
+		t.Errorf("expected rule set %+v, got %+v", ruleSet, decoded)
+	}
+	if _, err = DecodeRuleSet("not a rule set"); err == nil {
+		t.Error("expected an error for an invalid rule set")
+	}
+}
@@ -0,0 +1,226 @@
+package fwu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+// HostRules contains firewall rules translated from a host ruleset
+// (iptables-save or nft list ruleset output).
+type HostRules struct {
+	RulesV4In  []gsclient.FirewallRuleProperties
+	RulesV4Out []gsclient.FirewallRuleProperties
+	RulesV6In  []gsclient.FirewallRuleProperties
+	RulesV6Out []gsclient.FirewallRuleProperties
+}
+
+// RuleDiagnostic describes a problem found while translating a host ruleset.
+// If Skipped is true, the rule was not translated at all, otherwise it was
+// translated with an approximation (e.g. REJECT became drop).
+type RuleDiagnostic struct {
+	Line    int
+	Rule    string
+	Message string
+	Skipped bool
+}
+
+func (r RuleDiagnostic) String() string {
+	return fmt.Sprintf("line %d (%s): %s", r.Line, r.Rule, r.Message)
+}
+
+const (
+	chainDirectionIn  = "in"
+	chainDirectionOut = "out"
+)
+
+// hostRule is the protocol-independent representation of a single host rule.
+// Empty lists mean "any", every combination of the list values results in
+// one gridscale firewall rule.
+type hostRule struct {
+	direction string
+	forIPv4   bool
+	forIPv6   bool
+	protocols []gsclient.TransportLayerProtocol
+	srcCidrs  []string
+	dstCidrs  []string
+	srcPorts  []string
+	dstPorts  []string
+	action    string
+	comment   string
+}
+
+// expand converts a host rule to gridscale firewall rules. The order of
+// the rules is set later when the rules are appended to the target lists.
+func (r hostRule) expand() []gsclient.FirewallRuleProperties {
+	protocols := r.protocols
+	if len(protocols) == 0 {
+		// gridscale firewalls only filter tcp and udp, "any protocol" is covered by both
+		protocols = []gsclient.TransportLayerProtocol{gsclient.TCPTransport, gsclient.UDPTransport}
+	}
+	var rules []gsclient.FirewallRuleProperties
+	for _, protocol := range protocols {
+		for _, srcCidr := range orAny(r.srcCidrs) {
+			for _, dstCidr := range orAny(r.dstCidrs) {
+				for _, srcPort := range orAny(r.srcPorts) {
+					for _, dstPort := range orAny(r.dstPorts) {
+						rules = append(rules, gsclient.FirewallRuleProperties{
+							Protocol: protocol,
+							DstPort:  dstPort,
+							SrcPort:  srcPort,
+							SrcCidr:  srcCidr,
+							DstCidr:  dstCidr,
+							Action:   r.action,
+							Comment:  r.comment,
+						})
+					}
+				}
+			}
+		}
+	}
+	return rules
+}
+
+// addTo appends the expanded rule to the matching rule lists of the result.
+func (r hostRule) addTo(result *HostRules) {
+	expanded := r.expand()
+	if r.forIPv4 {
+		if r.direction == chainDirectionIn {
+			result.RulesV4In = appendWithOrder(result.RulesV4In, expanded)
+		} else {
+			result.RulesV4Out = appendWithOrder(result.RulesV4Out, expanded)
+		}
+	}
+	if r.forIPv6 {
+		if r.direction == chainDirectionIn {
+			result.RulesV6In = appendWithOrder(result.RulesV6In, expanded)
+		} else {
+			result.RulesV6Out = appendWithOrder(result.RulesV6Out, expanded)
+		}
+	}
+}
+
+// appendWithOrder appends rules to a rule list, numbering them
+// in the sequence they appear in the host ruleset.
+func appendWithOrder(rules, newRules []gsclient.FirewallRuleProperties) []gsclient.FirewallRuleProperties {
+	for _, rule := range newRules {
+		rule.Order = len(rules)
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func orAny(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+// normalizePort validates a port or a port range and returns it in the
+// gridscale format ("port" or "from:to"). sep is the range separator used
+// by the host ruleset (":" for iptables, "-" for nftables).
+func normalizePort(port, sep string) (string, error) {
+	parts := strings.Split(port, sep)
+	if len(parts) > 2 {
+		return "", fmt.Errorf("%q is not a valid port range", port)
+	}
+	if len(parts) == 2 {
+		// iptables allows open ranges like ":1024" or "1024:"
+		if parts[0] == "" {
+			parts[0] = "1"
+		}
+		if parts[1] == "" {
+			parts[1] = "65535"
+		}
+	}
+	var nums []int
+	for _, part := range parts {
+		num, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || num < 1 || num > 65535 {
+			return "", fmt.Errorf("%q is not a valid port, it has to be a number between 1 and 65535", port)
+		}
+		nums = append(nums, num)
+	}
+	if len(nums) == 2 && nums[0] > nums[1] {
+		return "", fmt.Errorf("%q is not a valid port range", port)
+	}
+	strs := make([]string, 0, len(nums))
+	for _, num := range nums {
+		strs = append(strs, strconv.Itoa(num))
+	}
+	return strings.Join(strs, ":"), nil
+}
+
+// parseProtocol converts a protocol name or number to a transport protocol
+// supported by gridscale firewalls.
+func parseProtocol(protocol string) (gsclient.TransportLayerProtocol, error) {
+	switch strings.ToLower(protocol) {
+	case "tcp", "6":
+		return gsclient.TCPTransport, nil
+	case "udp", "17":
+		return gsclient.UDPTransport, nil
+	default:
+		return "", fmt.Errorf("protocol %q is not supported, gridscale firewalls only filter tcp and udp", protocol)
+	}
+}
+
+// splitRuleFields splits a rule line into fields. Double quoted strings
+// are kept together (without the quotes) and nft anonymous sets like
+// "{ 22, 80 }" are returned as one field.
+func splitRuleFields(line string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inField := false
+	inQuotes := false
+	setDepth := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuotes:
+			if c == '\\' && i+1 < len(line) {
+				i++
+				current.WriteByte(line[i])
+			} else if c == '"' {
+				inQuotes = false
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '"':
+			inQuotes = true
+			inField = true
+		case c == '{':
+			setDepth++
+			inField = true
+			current.WriteByte(c)
+		case c == '}':
+			if setDepth == 0 {
+				return nil, errors.New("unbalanced '}'")
+			}
+			setDepth--
+			current.WriteByte(c)
+		case (c == ' ' || c == '\t') && setDepth == 0:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			inField = true
+			current.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if setDepth != 0 {
+		return nil, errors.New("unbalanced '{'")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,143 @@
+package fwu
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestParseIptablesSave(t *testing.T) {
+	type testCase struct {
+		Input           string
+		ExpectedRules   HostRules
+		ExpectedSkipped int
+	}
+	testCases := []testCase{
+		{
+			Input: `# Generated by iptables-save
+*nat
+:PREROUTING ACCEPT [0:0]
+-A PREROUTING -p tcp -m tcp --dport 8080 -j REDIRECT --to-ports 80
+COMMIT
+*filter
+:INPUT DROP [0:0]
+:FORWARD DROP [0:0]
+:OUTPUT ACCEPT [0:0]
+-A INPUT -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT
+-A INPUT -s 10.0.0.0/8 -p tcp -m tcp --dport 22 -m comment --comment "ssh from office" -j ACCEPT
+-A INPUT -p tcp -m multiport --dports 80,443 -j ACCEPT
+-A INPUT -s 192.168.0.1/32 -j DROP
+-A FORWARD -j ACCEPT
+-A OUTPUT -p udp -m udp --dport 1000:2000 -j REJECT --reject-with icmp-port-unreachable
+COMMIT
+`,
+			ExpectedRules: HostRules{
+				RulesV4In: []gsclient.FirewallRuleProperties{
+					{Order: 0, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.0/8", DstPort: "22", Action: "accept", Comment: "ssh from office"},
+					{Order: 1, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "accept"},
+					{Order: 2, Protocol: gsclient.TCPTransport, DstPort: "443", Action: "accept"},
+					{Order: 3, Protocol: gsclient.TCPTransport, SrcCidr: "192.168.0.1/32", Action: "drop"},
+					{Order: 4, Protocol: gsclient.UDPTransport, SrcCidr: "192.168.0.1/32", Action: "drop"},
+				},
+				RulesV4Out: []gsclient.FirewallRuleProperties{
+					{Order: 0, Protocol: gsclient.UDPTransport, DstPort: "1000:2000", Action: "drop"},
+				},
+			},
+			ExpectedSkipped: 2,
+		},
+	}
+	for _, test := range testCases {
+		rules, diags, err := ParseIptablesSave(test.Input, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(rules, test.ExpectedRules) {
+			t.Errorf("expected rules %+v, got %+v", test.ExpectedRules, rules)
+		}
+		if skipped := countSkipped(diags); skipped != test.ExpectedSkipped {
+			t.Errorf("expected %d skipped rules, got %d: %v", test.ExpectedSkipped, skipped, diags)
+		}
+	}
+}
+
+func TestParseNftRuleset(t *testing.T) {
+	input := `table inet filter { # handle 1
+	set blocked {
+		type ipv4_addr
+		elements = { 1.2.3.4 }
+	}
+
+	chain input { # handle 1
+		type filter hook input priority filter; policy drop;
+		ct state established,related accept
+		ip saddr 10.0.0.0/8 tcp dport 22 counter packets 0 bytes 0 accept comment "ssh from office"
+		ip6 saddr fd00::/8 udp dport { 53, 5000-5010 } accept
+		tcp dport 80 reject with tcp reset
+		ip saddr @blocked drop
+	}
+
+	chain forward {
+		type filter hook forward priority filter; policy drop;
+		accept
+	}
+}
+`
+	expectedRules := HostRules{
+		RulesV4In: []gsclient.FirewallRuleProperties{
+			{Order: 0, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.0/8", DstPort: "22", Action: "accept", Comment: "ssh from office"},
+			{Order: 1, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "drop"},
+		},
+		RulesV6In: []gsclient.FirewallRuleProperties{
+			{Order: 0, Protocol: gsclient.UDPTransport, SrcCidr: "fd00::/8", DstPort: "53", Action: "accept"},
+			{Order: 1, Protocol: gsclient.UDPTransport, SrcCidr: "fd00::/8", DstPort: "5000:5010", Action: "accept"},
+			{Order: 2, Protocol: gsclient.TCPTransport, DstPort: "80", Action: "drop"},
+		},
+	}
+	rules, diags, err := ParseNftRuleset(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Errorf("expected rules %+v, got %+v", expectedRules, rules)
+	}
+	if skipped := countSkipped(diags); skipped != 3 {
+		t.Errorf("expected 3 skipped rules, got %d: %v", skipped, diags)
+	}
+}
+
+func TestExportIptablesSaveRoundTrip(t *testing.T) {
+	rulesIn := []gsclient.FirewallRuleProperties{
+		{Order: 1, Protocol: gsclient.UDPTransport, DstPort: "53", Action: "accept", Comment: `dns "internal"`},
+		{Order: 0, Protocol: gsclient.TCPTransport, SrcCidr: "10.0.0.0/8", DstPort: "22", Action: "accept", Comment: "ssh"},
+	}
+	rulesOut := []gsclient.FirewallRuleProperties{
+		{Order: 0, Protocol: gsclient.TCPTransport, DstCidr: "192.168.0.0/16", SrcPort: "1024:65535", Action: "drop"},
+	}
+	exported := ExportIptablesSave(rulesIn, rulesOut)
+	rules, diags, err := ParseIptablesSave(exported, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+	expectedIn := []gsclient.FirewallRuleProperties{rulesIn[1], rulesIn[0]}
+	expectedIn[1].Order = 1
+	if !reflect.DeepEqual(rules.RulesV4In, expectedIn) {
+		t.Errorf("expected inbound rules %+v, got %+v\nexport:\n%s", expectedIn, rules.RulesV4In, exported)
+	}
+	if !reflect.DeepEqual(rules.RulesV4Out, rulesOut) {
+		t.Errorf("expected outbound rules %+v, got %+v\nexport:\n%s", rulesOut, rules.RulesV4Out, exported)
+	}
+}
+
+func countSkipped(diags []RuleDiagnostic) int {
+	var skipped int
+	for _, diag := range diags {
+		if diag.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
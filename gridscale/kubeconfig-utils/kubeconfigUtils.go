@@ -0,0 +1,116 @@
+package kcu
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Credentials are the connection details of a cluster, taken from the
+// current context of a kubeconfig. Certificates and keys are PEM encoded.
+type Credentials struct {
+	Host                 string
+	ClusterCACertificate string
+	ClientCertificate    string
+	ClientKey            string
+	Token                string
+}
+
+// kubeconfig contains the parts of a kubeconfig which are needed to connect to a cluster
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Token                 string `yaml:"token"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// ParseCredentials parses the credentials of the current context of a
+// kubeconfig. If no current context is set, the first context is used.
+func ParseCredentials(config string) (Credentials, error) {
+	var kc kubeconfig
+	if err := yaml.Unmarshal([]byte(config), &kc); err != nil {
+		return Credentials{}, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+	if len(kc.Contexts) == 0 {
+		return Credentials{}, errors.New("invalid kubeconfig: no context found")
+	}
+	contextIdx := 0
+	if kc.CurrentContext != "" {
+		contextIdx = -1
+		for i, context := range kc.Contexts {
+			if context.Name == kc.CurrentContext {
+				contextIdx = i
+				break
+			}
+		}
+		if contextIdx < 0 {
+			return Credentials{}, fmt.Errorf("invalid kubeconfig: current context %q not found", kc.CurrentContext)
+		}
+	}
+	context := kc.Contexts[contextIdx].Context
+
+	var creds Credentials
+	var err error
+	foundCluster := false
+	for _, cluster := range kc.Clusters {
+		if cluster.Name != context.Cluster {
+			continue
+		}
+		foundCluster = true
+		creds.Host = cluster.Cluster.Server
+		if creds.ClusterCACertificate, err = decodeData(cluster.Cluster.CertificateAuthorityData); err != nil {
+			return Credentials{}, fmt.Errorf("invalid kubeconfig: certificate-authority-data of cluster %q: %v", cluster.Name, err)
+		}
+		break
+	}
+	if !foundCluster {
+		return Credentials{}, fmt.Errorf("invalid kubeconfig: cluster %q not found", context.Cluster)
+	}
+	for _, user := range kc.Users {
+		if user.Name != context.User {
+			continue
+		}
+		if creds.ClientCertificate, err = decodeData(user.User.ClientCertificateData); err != nil {
+			return Credentials{}, fmt.Errorf("invalid kubeconfig: client-certificate-data of user %q: %v", user.Name, err)
+		}
+		if creds.ClientKey, err = decodeData(user.User.ClientKeyData); err != nil {
+			return Credentials{}, fmt.Errorf("invalid kubeconfig: client-key-data of user %q: %v", user.Name, err)
+		}
+		creds.Token = user.User.Token
+		return creds, nil
+	}
+	return Credentials{}, fmt.Errorf("invalid kubeconfig: user %q not found", context.User)
+}
+
+// decodeData decodes the base64 encoded *-data fields of a kubeconfig
+func decodeData(data string) (string, error) {
+	if data == "" {
+		return "", nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,71 @@
+package kcu
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseCredentials(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	config := `apiVersion: v1
+kind: Config
+current-context: admin@cluster
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+- name: cluster
+  cluster:
+    server: https://cluster.example.com:6443
+    certificate-authority-data: ` + encode("ca") + `
+contexts:
+- name: other@other
+  context:
+    cluster: other
+    user: other
+- name: admin@cluster
+  context:
+    cluster: cluster
+    user: admin
+users:
+- name: admin
+  user:
+    client-certificate-data: ` + encode("cert") + `
+    client-key-data: ` + encode("key") + `
+    token: secret
+`
+	type testCase struct {
+		Config        string
+		Expected      Credentials
+		ExpectedError bool
+	}
+	testCases := []testCase{
+		{
+			Config: config,
+			Expected: Credentials{
+				Host:                 "https://cluster.example.com:6443",
+				ClusterCACertificate: "ca",
+				ClientCertificate:    "cert",
+				ClientKey:            "key",
+				Token:                "secret",
+			},
+		},
+		{Config: "current-context: missing\ncontexts: []\n", ExpectedError: true},
+		{Config: "not: [valid", ExpectedError: true},
+	}
+	for _, test := range testCases {
+		creds, err := ParseCredentials(test.Config)
+		if test.ExpectedError {
+			if err == nil {
+				t.Errorf("expected an error for %q", test.Config)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if creds != test.Expected {
+			t.Errorf("expected %+v, got %+v", test.Expected, creds)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package kcu
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfigSections are the named lists of a kubeconfig which are merged
+var kubeconfigSections = []string{"clusters", "users", "contexts"}
+
+// MergeConfig merges the current context of config into the kubeconfig existing. The cluster,
+// user and context are named contextName, entries with the same name are replaced, all other
+// entries are kept. The current context of existing is only set if it has none. If existing is
+// empty, a new kubeconfig is returned.
+func MergeConfig(existing []byte, config, contextName string) ([]byte, error) {
+	cluster, user, context, err := currentContextEntries(config)
+	if err != nil {
+		return nil, err
+	}
+	cluster["name"] = contextName
+	user["name"] = contextName
+	context["name"] = contextName
+	contextFields, _ := context["context"].(map[string]interface{})
+	if contextFields == nil {
+		contextFields = make(map[string]interface{})
+	}
+	contextFields["cluster"] = contextName
+	contextFields["user"] = contextName
+	context["context"] = contextFields
+
+	kc := map[string]interface{}{}
+	if err = yaml.Unmarshal(existing, &kc); err != nil {
+		return nil, fmt.Errorf("invalid existing kubeconfig: %v", err)
+	}
+	if kc == nil {
+		kc = map[string]interface{}{}
+	}
+	if _, ok := kc["apiVersion"]; !ok {
+		kc["apiVersion"] = "v1"
+	}
+	if _, ok := kc["kind"]; !ok {
+		kc["kind"] = "Config"
+	}
+	for section, entry := range map[string]map[string]interface{}{"clusters": cluster, "users": user, "contexts": context} {
+		entries, err := namedEntries(kc, section)
+		if err != nil {
+			return nil, err
+		}
+		replaced := false
+		for i, existingEntry := range entries {
+			if existingEntry["name"] == contextName {
+				entries[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			entries = append(entries, entry)
+		}
+		kc[section] = entries
+	}
+	if currentContext, _ := kc["current-context"].(string); currentContext == "" {
+		kc["current-context"] = contextName
+	}
+	return yaml.Marshal(kc)
+}
+
+// RemoveConfig removes the cluster, user and context named contextName from the kubeconfig
+// existing. All other entries are kept.
+func RemoveConfig(existing []byte, contextName string) ([]byte, error) {
+	kc := map[string]interface{}{}
+	if err := yaml.Unmarshal(existing, &kc); err != nil {
+		return nil, fmt.Errorf("invalid existing kubeconfig: %v", err)
+	}
+	if kc == nil {
+		return existing, nil
+	}
+	for _, section := range kubeconfigSections {
+		entries, err := namedEntries(kc, section)
+		if err != nil {
+			return nil, err
+		}
+		kept := make([]map[string]interface{}, 0, len(entries))
+		for _, entry := range entries {
+			if entry["name"] != contextName {
+				kept = append(kept, entry)
+			}
+		}
+		kc[section] = kept
+	}
+	if kc["current-context"] == contextName {
+		kc["current-context"] = ""
+	}
+	return yaml.Marshal(kc)
+}
+
+// currentContextEntries returns copies of the cluster, user and context entries of the current
+// context of a kubeconfig. If no current context is set, the first context is used.
+func currentContextEntries(config string) (cluster, user, context map[string]interface{}, err error) {
+	kc := map[string]interface{}{}
+	if err = yaml.Unmarshal([]byte(config), &kc); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+	contexts, err := namedEntries(kc, "contexts")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(contexts) == 0 {
+		return nil, nil, nil, errors.New("invalid kubeconfig: no context found")
+	}
+	context = contexts[0]
+	if currentContext, _ := kc["current-context"].(string); currentContext != "" {
+		context = nil
+		for _, entry := range contexts {
+			if entry["name"] == currentContext {
+				context = entry
+				break
+			}
+		}
+		if context == nil {
+			return nil, nil, nil, fmt.Errorf("invalid kubeconfig: current context %q not found", currentContext)
+		}
+	}
+	contextFields, _ := context["context"].(map[string]interface{})
+	if cluster, err = findNamedEntry(kc, "clusters", contextFields["cluster"]); err != nil {
+		return nil, nil, nil, err
+	}
+	if user, err = findNamedEntry(kc, "users", contextFields["user"]); err != nil {
+		return nil, nil, nil, err
+	}
+	return copyEntry(cluster), copyEntry(user), copyEntry(context), nil
+}
+
+// namedEntries returns the entries of a named list of a kubeconfig (e.g. clusters)
+func namedEntries(kc map[string]interface{}, section string) ([]map[string]interface{}, error) {
+	var entries []map[string]interface{}
+	switch list := kc[section].(type) {
+	case nil:
+	case []map[string]interface{}:
+		entries = list
+	case []interface{}:
+		for _, item := range list {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid kubeconfig: invalid entry in %s", section)
+			}
+			entries = append(entries, entry)
+		}
+	default:
+		return nil, fmt.Errorf("invalid kubeconfig: %s is not a list", section)
+	}
+	return entries, nil
+}
+
+func findNamedEntry(kc map[string]interface{}, section string, name interface{}) (map[string]interface{}, error) {
+	entries, err := namedEntries(kc, section)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry["name"] == name {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid kubeconfig: %s entry %q not found", section, name)
+}
+
+func copyEntry(entry map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(entry))
+	for key, value := range entry {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyEntry(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,93 @@
+package kcu
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeAndRemoveConfig(t *testing.T) {
+	config := `apiVersion: v1
+kind: Config
+current-context: admin@cluster
+clusters:
+- name: cluster
+  cluster:
+    server: https://cluster.example.com:6443
+contexts:
+- name: admin@cluster
+  context:
+    cluster: cluster
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+	existing := `apiVersion: v1
+kind: Config
+current-context: other
+preferences: {}
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+- name: gsk-foo
+  cluster:
+    server: https://old.example.com
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+users:
+- name: other
+  user:
+    token: other
+`
+	type testCase struct {
+		Existing               string
+		ExpectedCurrentContext string
+		ExpectedClusters       int
+	}
+	for _, test := range []testCase{
+		{Existing: "", ExpectedCurrentContext: "gsk-foo", ExpectedClusters: 1},
+		{Existing: existing, ExpectedCurrentContext: "other", ExpectedClusters: 2},
+	} {
+		merged, err := MergeConfig([]byte(test.Existing), config, "gsk-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the merged kubeconfig must be parsable and contain the cluster under the new context name
+		var kc map[string]interface{}
+		if err = yaml.Unmarshal(merged, &kc); err != nil {
+			t.Fatal(err)
+		}
+		if kc["current-context"] != test.ExpectedCurrentContext {
+			t.Errorf("expected current context %q, got %q", test.ExpectedCurrentContext, kc["current-context"])
+		}
+		if clusters := kc["clusters"].([]interface{}); len(clusters) != test.ExpectedClusters {
+			t.Errorf("expected %d clusters, got %d", test.ExpectedClusters, len(clusters))
+		}
+		kc["current-context"] = "gsk-foo"
+		withCurrentContext, _ := yaml.Marshal(kc)
+		creds, err := ParseCredentials(string(withCurrentContext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if creds.Host != "https://cluster.example.com:6443" || creds.Token != "secret" {
+			t.Errorf("unexpected credentials of merged context: %+v", creds)
+		}
+
+		removed, err := RemoveConfig(merged, "gsk-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = yaml.Unmarshal(removed, &kc); err != nil {
+			t.Fatal(err)
+		}
+		if clusters := kc["clusters"].([]interface{}); len(clusters) != test.ExpectedClusters-1 {
+			t.Errorf("expected %d clusters after remove, got %d", test.ExpectedClusters-1, len(clusters))
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package kcu
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// nodeList contains the parts of a v1.NodeList which are needed to check the readiness of nodes
+type nodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// NewHTTPClient returns an HTTP client which authenticates against the API server of a
+// cluster with the given credentials.
+func NewHTTPClient(creds Credentials, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if creds.ClusterCACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(creds.ClusterCACertificate)) {
+			return nil, errors.New("invalid cluster CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if creds.ClientCertificate != "" && creds.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(creds.ClientCertificate), []byte(creds.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// CheckAPIServerReady checks if the `/readyz` endpoint of the API server reports ready.
+func CheckAPIServerReady(ctx context.Context, client *http.Client, creds Credentials) error {
+	body, err := get(ctx, client, creds, "/readyz")
+	if err != nil {
+		return fmt.Errorf("API server is not ready: %v", err)
+	}
+	if strings.TrimSpace(string(body)) != "ok" {
+		return fmt.Errorf("API server is not ready: %s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// CountReadyNodes returns the number of nodes which have the condition `Ready`, grouped by
+// the value of the label poolLabel. If poolLabel is empty, all nodes are counted under "".
+func CountReadyNodes(ctx context.Context, client *http.Client, creds Credentials, poolLabel string) (map[string]int, error) {
+	body, err := get(ctx, client, creds, "/api/v1/nodes")
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	var nodes nodeList
+	if err = json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("error decoding nodes: %v", err)
+	}
+	readyNodes := make(map[string]int)
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == "Ready" && condition.Status == "True" {
+				var pool string
+				if poolLabel != "" {
+					pool = node.Metadata.Labels[poolLabel]
+				}
+				readyNodes[pool]++
+				break
+			}
+		}
+	}
+	return readyNodes, nil
+}
+
+// get sends an authenticated GET request to the API server and returns the body of the response
+func get(ctx context.Context, client *http.Client, creds Credentials, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(creds.Host, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+creds.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
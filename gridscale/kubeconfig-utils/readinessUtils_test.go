@@ -0,0 +1,60 @@
+package kcu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCountReadyNodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/readyz":
+			w.Write([]byte("ok"))
+		case "/api/v1/nodes":
+			w.Write([]byte(`{"items": [
+				{"metadata": {"name": "a-0", "labels": {"pool": "a"}}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+				{"metadata": {"name": "a-1", "labels": {"pool": "a"}}, "status": {"conditions": [{"type": "Ready", "status": "False"}]}},
+				{"metadata": {"name": "b-0", "labels": {"pool": "b"}}, "status": {"conditions": [{"type": "MemoryPressure", "status": "False"}, {"type": "Ready", "status": "True"}]}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	creds := Credentials{Host: server.URL, Token: "secret"}
+	client, err := NewHTTPClient(creds, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = CheckAPIServerReady(context.Background(), client, creds); err != nil {
+		t.Errorf("expected API server to be ready, got %v", err)
+	}
+	if err = CheckAPIServerReady(context.Background(), client, Credentials{Host: server.URL}); err == nil {
+		t.Error("expected error for unauthorized request")
+	}
+
+	type testCase struct {
+		PoolLabel string
+		Expected  map[string]int
+	}
+	for _, test := range []testCase{
+		{PoolLabel: "pool", Expected: map[string]int{"a": 1, "b": 1}},
+		{PoolLabel: "", Expected: map[string]int{"": 2}},
+	} {
+		readyNodes, err := CountReadyNodes(context.Background(), client, creds, test.PoolLabel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(readyNodes, test.Expected) {
+			t.Errorf("pool label %q: expected %v, got %v", test.PoolLabel, test.Expected, readyNodes)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package paasu
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+// Parameter types of the parameters schema of PaaS templates (python-cerberus compatible)
+const (
+	typeInteger = "integer"
+	typeFloat   = "float"
+	typeNumber  = "number"
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeList    = "list"
+	typeDict    = "dict"
+)
+
+// Options are the options of the validation of service parameters
+type Options struct {
+	// AttributePaths maps the paths of parameters (e.g. "pools.*.ram") to the paths of the
+	// attributes which set them (e.g. "node_pool.*.memory"), which are used in the error
+	// messages. A "*" matches a list index. Parameters without mapping are reported by their path.
+	AttributePaths map[string]string
+
+	// CheckRequired reports required parameters without default value, which are not set.
+	CheckRequired bool
+
+	// RejectUnknown reports parameters which are not in the parameters schema.
+	RejectUnknown bool
+
+	// Current are the current parameters of the service. Immutable parameters must not change.
+	Current map[string]interface{}
+}
+
+// ValidateParameters validates service parameters against the parameters schema of a PaaS template.
+// It checks the types, ranges, allowed values, regular expressions, required and immutable
+// parameters and validates nested parameters (lists of dicts) recursively. It returns one error
+// message per invalid parameter.
+func ValidateParameters(parametersSchema map[string]gsclient.Parameter, params map[string]interface{}, opts Options) []string {
+	v := validator{opts: opts}
+	v.validateDict(parametersSchema, params, opts.Current, "", opts.CheckRequired)
+	return v.errorMessages
+}
+
+type validator struct {
+	opts          Options
+	errorMessages []string
+}
+
+func (v *validator) addError(path, format string, args ...interface{}) {
+	v.errorMessages = append(v.errorMessages, fmt.Sprintf("Invalid '%s' value. %s\n", v.attributePath(path), fmt.Sprintf(format, args...)))
+}
+
+// validateDict validates the fields of a dict, sorted by name to get stable error messages
+func (v *validator) validateDict(parametersSchema map[string]gsclient.Parameter, params, current map[string]interface{}, prefix string, checkRequired bool) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := prefix + name
+		parameter, ok := parametersSchema[name]
+		if !ok {
+			if v.opts.RejectUnknown {
+				v.errorMessages = append(v.errorMessages, fmt.Sprintf("Unknown parameter '%s'. Valid parameters are: %s\n", v.attributePath(path), strings.Join(sortedKeys(parametersSchema), ", ")))
+			}
+			continue
+		}
+		var currentValue interface{}
+		if current != nil {
+			currentValue = current[name]
+		}
+		v.validateValue(parameter, params[name], currentValue, path)
+	}
+	if !checkRequired {
+		return
+	}
+	for _, name := range sortedKeys(parametersSchema) {
+		parameter := parametersSchema[name]
+		if _, ok := params[name]; !ok && parameter.Required && parameter.Default == nil {
+			v.errorMessages = append(v.errorMessages, fmt.Sprintf("Missing required parameter '%s'.\n", v.attributePath(prefix+name)))
+		}
+	}
+}
+
+func (v *validator) validateValue(parameter gsclient.Parameter, value, currentValue interface{}, path string) {
+	if value == nil {
+		return
+	}
+	if parameter.Immutable && currentValue != nil && fmt.Sprint(currentValue) != fmt.Sprint(value) {
+		v.addError(path, "Cannot change the parameter, because it is immutable.")
+		return
+	}
+	switch parameter.Type {
+	case typeInteger, typeFloat, typeNumber:
+		number, ok := toFloat(value)
+		if !ok || (parameter.Type == typeInteger && number != math.Trunc(number)) {
+			v.addError(path, "Value must be of type %s.", parameter.Type)
+			return
+		}
+		if parameter.Min != 0 || parameter.Max != 0 {
+			if number < float64(parameter.Min) || (parameter.Max != 0 && number > float64(parameter.Max)) {
+				v.addError(path, "Value must stay between %d and %d.", parameter.Min, parameter.Max)
+				return
+			}
+		}
+		v.validateAllowed(parameter, strconv.FormatFloat(number, 'f', -1, 64), path)
+	case typeString:
+		str, ok := value.(string)
+		if !ok {
+			v.addError(path, "Value must be of type %s.", parameter.Type)
+			return
+		}
+		if parameter.Regex != "" && str != "" {
+			regex, err := regexp.Compile(parameter.Regex)
+			if err == nil && !regex.MatchString(str) {
+				if parameter.Default != nil && parameter.Default != "" {
+					v.addError(path, "Value needs to match RegEx: '%s'. Example value: '%v'.", parameter.Regex, parameter.Default)
+				} else {
+					v.addError(path, "Value needs to match RegEx: '%s'.", parameter.Regex)
+				}
+				return
+			}
+		}
+		v.validateAllowed(parameter, str, path)
+	case typeBoolean:
+		if _, ok := value.(bool); !ok {
+			v.addError(path, "Value must be of type %s.", parameter.Type)
+		}
+	case typeList:
+		items, ok := toList(value)
+		if !ok {
+			v.addError(path, "Value must be of type %s.", parameter.Type)
+			return
+		}
+		currentItems, _ := toList(currentValue)
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s.%d", path, i)
+			if parameter.Schema.Type == typeDict {
+				fields, ok := toDict(item)
+				if !ok {
+					v.addError(itemPath, "Value must be of type %s.", typeDict)
+					continue
+				}
+				var currentFields map[string]interface{}
+				if i < len(currentItems) {
+					currentFields, _ = toDict(currentItems[i])
+				}
+				v.validateDict(parameter.Schema.Schema, fields, currentFields, itemPath+".", v.opts.CheckRequired)
+				continue
+			}
+			if parameter.Schema.Type != "" {
+				v.validateValue(gsclient.Parameter{Type: parameter.Schema.Type}, item, nil, itemPath)
+			}
+			if len(parameter.Allowed) != 0 {
+				v.validateAllowed(parameter, fmt.Sprint(item), itemPath)
+			}
+		}
+	case typeDict:
+		if _, ok := toDict(value); !ok {
+			v.addError(path, "Value must be of type %s.", parameter.Type)
+		}
+	}
+}
+
+func (v *validator) validateAllowed(parameter gsclient.Parameter, value, path string) {
+	if len(parameter.Allowed) == 0 {
+		return
+	}
+	for _, allowedValue := range parameter.Allowed {
+		if value == allowedValue {
+			return
+		}
+	}
+	v.addError(path, "Value must be one of these:\n\t%s", strings.Join(parameter.Allowed, "\n\t"))
+}
+
+// attributePath translates the path of a parameter to the path of its attribute
+func (v *validator) attributePath(path string) string {
+	segments := strings.Split(path, ".")
+	for pattern, attribute := range v.opts.AttributePaths {
+		patternSegments := strings.Split(pattern, ".")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+		var indices []string
+		matched := true
+		for i, patternSegment := range patternSegments {
+			if patternSegment == "*" {
+				if _, err := strconv.Atoi(segments[i]); err == nil {
+					indices = append(indices, segments[i])
+					continue
+				}
+			}
+			if patternSegment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, index := range indices {
+			attribute = strings.Replace(attribute, "*", index, 1)
+		}
+		return attribute
+	}
+	return path
+}
+
+func sortedKeys(parametersSchema map[string]gsclient.Parameter) []string {
+	keys := make([]string, 0, len(parametersSchema))
+	for key := range parametersSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toList(value interface{}) ([]interface{}, bool) {
+	switch list := value.(type) {
+	case []interface{}:
+		return list, true
+	case []string:
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		return items, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func toDict(value interface{}) (map[string]interface{}, bool) {
+	dict, ok := value.(map[string]interface{})
+	return dict, ok
+}
@@ -0,0 +1,97 @@
+package paasu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestValidateParameters(t *testing.T) {
+	parametersSchema := map[string]gsclient.Parameter{
+		"max_connections": {Type: "integer", Min: 1, Max: 100},
+		"binlog_format":   {Type: "string", Allowed: []string{"ROW", "MIXED"}},
+		"bucket":          {Type: "string", Regex: "^[a-z0-9-]+$"},
+		"log_bin":         {Type: "boolean"},
+		"cidr":            {Type: "string", Immutable: true},
+		"tier":            {Type: "string", Required: true},
+		"pools": {
+			Type: "list",
+			Schema: gsclient.Schema{
+				Type: "dict",
+				Schema: map[string]gsclient.Parameter{
+					"name": {Type: "string", Required: true},
+					"ram":  {Type: "integer", Min: 2, Max: 64},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		opts     Options
+		expected []string
+	}{
+		{
+			name: "valid parameters",
+			params: map[string]interface{}{
+				"max_connections": 10,
+				"binlog_format":   "ROW",
+				"bucket":          "my-bucket",
+				"log_bin":         true,
+				"pools":           []map[string]interface{}{{"name": "pool-0", "ram": float64(4)}},
+			},
+		},
+		{
+			name:     "out of range",
+			params:   map[string]interface{}{"max_connections": 101},
+			expected: []string{"Invalid 'max_connections' value. Value must stay between 1 and 100.\n"},
+		},
+		{
+			name:     "wrong type",
+			params:   map[string]interface{}{"max_connections": "ten", "log_bin": "yes"},
+			expected: []string{"Invalid 'log_bin' value. Value must be of type boolean.\n", "Invalid 'max_connections' value. Value must be of type integer.\n"},
+		},
+		{
+			name:     "not allowed",
+			params:   map[string]interface{}{"binlog_format": "STATEMENT"},
+			expected: []string{"Invalid 'binlog_format' value. Value must be one of these:\n\tROW\n\tMIXED\n"},
+		},
+		{
+			name:     "regex mismatch",
+			params:   map[string]interface{}{"bucket": "My_Bucket"},
+			expected: []string{"Invalid 'bucket' value. Value needs to match RegEx: '^[a-z0-9-]+$'.\n"},
+		},
+		{
+			name:   "nested parameters with attribute paths",
+			params: map[string]interface{}{"pools": []interface{}{map[string]interface{}{"name": "a", "ram": 4}, map[string]interface{}{"name": "b", "ram": 128}}},
+			opts: Options{AttributePaths: map[string]string{
+				"pools.*.ram": "node_pool.*.memory",
+			}},
+			expected: []string{"Invalid 'node_pool.1.memory' value. Value must stay between 2 and 64.\n"},
+		},
+		{
+			name:     "immutable parameter",
+			params:   map[string]interface{}{"cidr": "10.0.0.0/16"},
+			opts:     Options{Current: map[string]interface{}{"cidr": "10.244.0.0/16"}},
+			expected: []string{"Invalid 'cidr' value. Cannot change the parameter, because it is immutable.\n"},
+		},
+		{
+			name:   "required and unknown parameters",
+			params: map[string]interface{}{"foo": "bar"},
+			opts:   Options{CheckRequired: true, RejectUnknown: true, AttributePaths: map[string]string{"foo": "parameter.foo"}},
+			expected: []string{
+				"Unknown parameter 'parameter.foo'. Valid parameters are: binlog_format, bucket, cidr, log_bin, max_connections, pools, tier\n",
+				"Missing required parameter 'tier'.\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessages := ValidateParameters(parametersSchema, test.params, test.opts)
+			if strings.Join(errorMessages, "") != strings.Join(test.expected, "") {
+				t.Errorf("expected %q, got %q", test.expected, errorMessages)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// paasPasswordParameter is the service parameter which sets the password of a PaaS service
+const paasPasswordParameter = "password"
+
+// addPaaSCredentialsSchema adds the attributes to rotate or supply the password of a PaaS service to a schema
+func addPaaSCredentialsSchema(s map[string]*schema.Schema) {
+	s["password_rotation_trigger"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Any change of this value renews the credentials of the service (e.g. the timestamp of a `time_rotating` resource).",
+		Optional:    true,
+	}
+	s["password_wo"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Write-only password of the service. It is sent when the service is created and when `password_wo_version` changes. Only supported by templates with a `password` parameter.",
+		Optional:    true,
+		Sensitive:   true,
+		WriteOnly:   true,
+	}
+	s["password_wo_version"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Description: "Version of `password_wo`. Change it to set the password of an existing service.",
+		Optional:    true,
+	}
+	s["password_rotated_at"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Time (RFC3339) the credentials of the service were last set or rotated by Terraform.",
+		Computed:    true,
+	}
+}
+
+// paasWriteOnlyPassword returns the write-only password of a configuration, if it is set
+func paasWriteOnlyPassword(rawConfig cty.Value) (string, bool) {
+	if rawConfig.IsNull() || !rawConfig.IsKnown() || !rawConfig.Type().IsObjectType() || !rawConfig.Type().HasAttribute("password_wo") {
+		return "", false
+	}
+	password := rawConfig.GetAttr("password_wo")
+	if password.IsNull() || !password.IsKnown() || password.AsString() == "" {
+		return "", false
+	}
+	return password.AsString(), true
+}
+
+// checkPaaSPasswordSupport checks that the template supports a supplied password. Templates
+// without parameters schema are not checked.
+func checkPaaSPasswordSupport(d *schema.ResourceDiff, template gsclient.PaaSTemplate) error {
+	if _, ok := paasWriteOnlyPassword(d.GetRawConfig()); ok && len(template.Properties.ParametersSchema) > 0 {
+		if _, supported := template.Properties.ParametersSchema[paasPasswordParameter]; !supported {
+			return fmt.Errorf("'password_wo' is not supported by the service template %s", template.Properties.Name)
+		}
+	}
+	return nil
+}
+
+// customizePaaSCredentialsDiff marks the credentials of a PaaS service as changing, if they are rotated
+func customizePaaSCredentialsDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !(d.HasChange("password_rotation_trigger") || d.HasChange("password_wo_version")) {
+		return nil
+	}
+	for _, key := range []string{"password", "connection_uri", "password_rotated_at"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paasPasswordParameters adds the supplied password to the parameters of a PaaS service, if it is
+// set and the service is created or `password_wo_version` changed
+func paasPasswordParameters(d *schema.ResourceData, params map[string]interface{}) {
+	if d.Id() != "" && !d.HasChange("password_wo_version") {
+		return
+	}
+	if password, ok := paasWriteOnlyPassword(d.GetRawConfig()); ok {
+		params[paasPasswordParameter] = password
+	}
+}
+
+// rotatePaaSCredentials renews the credentials of a PaaS service, if `password_rotation_trigger`
+// changed, and records the time the credentials were set or rotated
+func rotatePaaSCredentials(ctx context.Context, client *gsclient.Client, d *schema.ResourceData, created bool) error {
+	rotated := created || d.HasChange("password_wo_version")
+	if !created && d.HasChange("password_rotation_trigger") {
+		// The renew endpoint is not specific to k8s, it renews the credentials of any PaaS service
+		if err := client.RenewK8sCredentials(ctx, d.Id()); err != nil {
+			return fmt.Errorf("error renewing credentials: %v", err)
+		}
+		rotated = true
+	}
+	if !rotated {
+		return nil
+	}
+	return d.Set("password_rotated_at", time.Now().UTC().Format(time.RFC3339))
+}
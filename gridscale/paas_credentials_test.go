@@ -0,0 +1,68 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceGridscaleRedisStorePasswordRotation(t *testing.T) {
+	var object gsclient.PaaSService
+	var password string
+	name := fmt.Sprintf("redis_store-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceGridscalePaaSDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleRedisStoreConfigPasswordRotation(name, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscalePaaSExists("gridscale_redis_store.test", &object),
+					resource.TestCheckResourceAttrSet("gridscale_redis_store.test", "password_rotated_at"),
+					testAccCheckResourceGridscalePaaSPassword("gridscale_redis_store.test", &password, false),
+				),
+			},
+			{
+				Config: testAccCheckResourceGridscaleRedisStoreConfigPasswordRotation(name, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscalePaaSExists("gridscale_redis_store.test", &object),
+					testAccCheckResourceGridscalePaaSPassword("gridscale_redis_store.test", &password, true),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckResourceGridscalePaaSPassword records the password of a PaaS service and
+// checks that it differs from the previously recorded one, if changed is true
+func testAccCheckResourceGridscalePaaSPassword(n string, password *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		newPassword := rs.Primary.Attributes["password"]
+		if changed && newPassword == *password {
+			return fmt.Errorf("password of %s has not been rotated", n)
+		}
+		*password = newPassword
+		return nil
+	}
+}
+
+func testAccCheckResourceGridscaleRedisStoreConfigPasswordRotation(name, trigger string) string {
+	return fmt.Sprintf(`
+resource "gridscale_redis_store" "test" {
+	name = "%s"
+	release = "7"
+	performance_class = "standard"
+	password_rotation_trigger = "%s"
+}
+`, name, trigger)
+}
@@ -0,0 +1,755 @@
+package gridscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+	paasu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/paas-utils"
+)
+
+// paasEngine describes a PaaS service engine (e.g. MariaDB). The resource and the data source
+// of every engine in paasEngines are generated from its description, so all engines share the
+// same template lookup, validation, and CRUD behaviour.
+type paasEngine struct {
+	// name is the name of the resource and the data source (e.g. gridscale_mariadb)
+	name string
+	// flavour is the flavour of the PaaS templates of the engine
+	flavour string
+	// displayName is used in descriptions and error messages (e.g. MariaDB)
+	displayName string
+	// serviceName is used in descriptions (e.g. MariaDB service)
+	serviceName string
+	// logName is used in the error prefixes (e.g. mariadb)
+	logName string
+	// deprecationMessage marks the resource as deprecated, if set
+	deprecationMessage string
+	// performanceClasses restricts `performance_class` to the given values, if set
+	performanceClasses []string
+	// autoscaling adds the `max_core_count` attribute
+	autoscaling bool
+	// parameters are the service parameters which are set by top-level attributes
+	parameters []paasEngineParameter
+	// blocks are the service parameters which are grouped in a block (e.g. s3_backup)
+	blocks []paasEngineBlock
+	// settings are attributes which only configure the provider and are not sent to the API
+	settings map[string]*schema.Schema
+	// validateParameters adds engine specific checks to the validation of the parameters
+	validateParameters func(d *schema.ResourceDiff, v *paasParameterValidation)
+	// preflightCheck checks the bucket configured by the parameters, if set
+	preflightCheck *s3PreflightCheck
+}
+
+// paasEngineParameter maps an attribute to a service parameter. Parameters of attributes which
+// are computed are only sent if they are set, all other parameters are always sent.
+type paasEngineParameter struct {
+	attribute string
+	parameter string
+	schema    *schema.Schema
+}
+
+// paasEngineBlock groups service parameters in a block with at most one element. The parameters
+// are only sent if the block is set, the block is only read if its first parameter is set. At plan
+// time, the template of the service must support all parameters of a block which is set.
+type paasEngineBlock struct {
+	name        string
+	description string
+	parameters  []paasEngineParameter
+	// createOnly blocks are only sent when the service is created and are not read. Changing
+	// them replaces the service.
+	createOnly bool
+}
+
+// paasEngines is the registry of all PaaS service engines
+var paasEngines = []*paasEngine{
+	&mariaDBEngine,
+	&mySQLEngine,
+	&mySQL8_0Engine,
+	&postgreSQLEngine,
+	&msSQLServerEngine,
+	&redisStoreEngine,
+	&redisCacheEngine,
+	&memcachedEngine,
+}
+
+// resource generates the resource of the engine
+func (e *paasEngine) resource() *schema.Resource {
+	return &schema.Resource{
+		DeprecationMessage: e.deprecationMessage,
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := e.create(d, meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return e.verify(ctx, d)
+		},
+		Read: e.read,
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := e.update(d, meta); err != nil {
+				return diag.FromErr(err)
+			}
+			return e.verify(ctx, d)
+		},
+		Delete: e.delete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: e.customizeDiff,
+		Schema:        e.schema(),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+	}
+}
+
+// dataSource generates the data source of the engine. It has the attributes of the resource
+// (without the provider settings and create only blocks) as computed attributes.
+func (e *paasEngine) dataSource() *schema.Resource {
+	dataSourceSchema := map[string]*schema.Schema{
+		"resource_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "ID of a resource",
+			ValidateFunc: validation.NoZeroValues,
+		},
+	}
+	for key, s := range e.schema() {
+		if _, ok := e.settings[key]; ok {
+			continue
+		}
+		dataSourceSchema[key] = computedSchema(s)
+	}
+	// create only blocks and the credentials inputs are not read
+	for _, block := range e.blocks {
+		if block.createOnly {
+			delete(dataSourceSchema, block.name)
+		}
+	}
+	for _, key := range []string{"password_rotation_trigger", "password_wo", "password_wo_version", "password_rotated_at"} {
+		delete(dataSourceSchema, key)
+	}
+	return &schema.Resource{
+		Read:   e.dataSourceRead,
+		Schema: dataSourceSchema,
+	}
+}
+
+// computedSchema converts the schema of a resource attribute to a computed attribute
+func computedSchema(s *schema.Schema) *schema.Schema {
+	computed := &schema.Schema{
+		Type:        s.Type,
+		Description: s.Description,
+		Sensitive:   s.Sensitive,
+		Computed:    true,
+	}
+	switch elem := s.Elem.(type) {
+	case *schema.Resource:
+		elemSchema := make(map[string]*schema.Schema)
+		for key, nested := range elem.Schema {
+			elemSchema[key] = computedSchema(nested)
+		}
+		computed.Elem = &schema.Resource{Schema: elemSchema}
+	case *schema.Schema:
+		computed.Elem = &schema.Schema{Type: elem.Type}
+	}
+	return computed
+}
+
+func (e *paasEngine) schema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Description:  "The human-readable name of the object. It supports the full UTF-8 character set, with a maximum of 64 characters.",
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"release": {
+			Type: schema.TypeString,
+			Description: fmt.Sprintf(`The %s release of this instance.\n
+				For convenience, please use gscloud https://github.com/gridscale/gscloud to get the list of available %s releases.`, e.displayName, e.serviceName),
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"performance_class": {
+			Type:         schema.TypeString,
+			Description:  fmt.Sprintf("Performance class of %s.", e.serviceName),
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Username for %s. It is used to connect to the %s instance.", e.serviceName, e.displayName),
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Password for %s. It is used to connect to the %s instance.", e.serviceName, e.displayName),
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"listen_port": {
+			Type:        schema.TypeSet,
+			Description: fmt.Sprintf("The port numbers where this %s accepts connections.", e.serviceName),
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"host": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"port": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"security_zone_uuid": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Security zone UUID linked to %s.", e.serviceName),
+			Deprecated:  "Security zone is deprecated for gridSQL, gridStore, and gridFs. Please consider to use private network instead.",
+			Optional:    true,
+			ForceNew:    true,
+			Computed:    true,
+		},
+		"network_uuid": {
+			Type:        schema.TypeString,
+			Description: "The UUID of the network that the service is attached to.",
+			Optional:    true,
+			Computed:    true,
+		},
+		"service_template_uuid": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("PaaS service template that %s uses.", e.serviceName),
+			Computed:    true,
+		},
+		"service_template_category": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The template service's category used to create the service.",
+		},
+		"usage_in_minutes": {
+			Type:        schema.TypeInt,
+			Description: fmt.Sprintf("Number of minutes that %s is in use.", e.serviceName),
+			Computed:    true,
+		},
+		"change_time": {
+			Type:        schema.TypeString,
+			Description: "Time of the last change.",
+			Computed:    true,
+		},
+		"create_time": {
+			Type:        schema.TypeString,
+			Description: "Date time this service has been created.",
+			Computed:    true,
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Current status of %s.", e.serviceName),
+			Computed:    true,
+		},
+		"labels": {
+			Type:        schema.TypeSet,
+			Description: "List of labels.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+	if len(e.performanceClasses) > 0 {
+		s["performance_class"].ValidateFunc = validation.StringInSlice(e.performanceClasses, false)
+	}
+	if e.autoscaling {
+		s["max_core_count"] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Description:  fmt.Sprintf("Maximum CPU core count. The %s instance's CPU core count will be autoscaled based on the workload. The number of cores stays between 1 and `max_core_count`.", e.displayName),
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.NoZeroValues,
+		}
+	}
+	for _, p := range e.parameters {
+		s[p.attribute] = p.schema
+	}
+	for _, block := range e.blocks {
+		blockSchema := make(map[string]*schema.Schema)
+		for _, p := range block.parameters {
+			blockSchema[p.attribute] = p.schema
+			if block.createOnly {
+				forceNew := *p.schema
+				forceNew.ForceNew = true
+				blockSchema[p.attribute] = &forceNew
+			}
+		}
+		s[block.name] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			ForceNew:    block.createOnly,
+			MaxItems:    1,
+			Description: block.description,
+			Elem:        &schema.Resource{Schema: blockSchema},
+		}
+	}
+	for key, setting := range e.settings {
+		s[key] = setting
+	}
+	addPaaSConnectionSchema(s)
+	addPaaSCredentialsSchema(s)
+	return s
+}
+
+// parameterAttributes maps the attribute paths of the engine to the parameters of the service
+func (e *paasEngine) parameterAttributes() map[string]string {
+	attributes := make(map[string]string)
+	for _, p := range e.parameters {
+		attributes[p.attribute] = p.parameter
+	}
+	for _, block := range e.blocks {
+		for _, p := range block.parameters {
+			attributes[fmt.Sprintf("%s.0.%s", block.name, p.attribute)] = p.parameter
+		}
+	}
+	return attributes
+}
+
+// findTemplate returns the template of the engine with the given release and performance class.
+// If there is none, the error lists the valid combinations.
+func (e *paasEngine) findTemplate(ctx context.Context, client *gsclient.Client, release, performanceClass string) (gsclient.PaaSTemplate, error) {
+	paasTemplates, err := client.GetPaaSTemplateList(ctx)
+	if err != nil {
+		return gsclient.PaaSTemplate{}, err
+	}
+	var releases []string
+	releaseWPerfClasses := make(map[string][]string)
+	for _, template := range paasTemplates {
+		if template.Properties.Flavour != e.flavour {
+			continue
+		}
+		if template.Properties.Release == release && template.Properties.PerformanceClass == performanceClass {
+			return template, nil
+		}
+		if _, ok := releaseWPerfClasses[template.Properties.Release]; !ok {
+			releases = append(releases, template.Properties.Release)
+		}
+		releaseWPerfClasses[template.Properties.Release] = append(releaseWPerfClasses[template.Properties.Release], template.Properties.PerformanceClass)
+	}
+	errMess := fmt.Sprintf("release %v with performance class %s is not a valid %s release/performance class. Valid releases with corresponding performance classes are:\n\t", release, performanceClass, e.displayName)
+	for _, r := range releases {
+		errMess += fmt.Sprintf("release %s has following performance classes: %s\n\t", r, strings.Join(releaseWPerfClasses[r], ", "))
+	}
+	return gsclient.PaaSTemplate{}, errors.New(errMess)
+}
+
+func (e *paasEngine) customizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	template, err := e.findTemplate(ctx, client, d.Get("release").(string), d.Get("performance_class").(string))
+	if err != nil {
+		return err
+	}
+	v := newPaaSParameterValidation(d, e.parameterAttributes())
+	if maxNCore, ok := d.GetOk("max_core_count"); ok && e.autoscaling {
+		autoscalingNCore := template.Properties.Autoscaling.Cores
+		if autoscalingNCore.Min > maxNCore.(int) || maxNCore.(int) > autoscalingNCore.Max {
+			v.addError(fmt.Sprintf("Invalid 'max_core_count' value. Value must stays between %d and %d\n", autoscalingNCore.Min, autoscalingNCore.Max))
+		}
+	}
+	if e.validateParameters != nil {
+		e.validateParameters(d, v)
+	}
+	for _, message := range e.unsupportedBlocks(d, template) {
+		v.addError(message)
+	}
+	if err = checkPaaSPasswordSupport(d, template); err != nil {
+		v.addError(err.Error() + "\n")
+	}
+	if err = v.validate(template, paasu.Options{}); err != nil {
+		return err
+	}
+	if err = customizePaaSCredentialsDiff(ctx, d, meta); err != nil {
+		return err
+	}
+	if e.preflightCheck != nil {
+		return e.preflightCheck.validateDiff(ctx, d)
+	}
+	return nil
+}
+
+// unsupportedBlocks returns an error message for each block which is set, but whose parameters
+// are not supported by the template. Templates without parameters schema are not checked.
+func (e *paasEngine) unsupportedBlocks(d *schema.ResourceDiff, template gsclient.PaaSTemplate) []string {
+	var errorMessages []string
+	parametersSchema := template.Properties.ParametersSchema
+	if len(parametersSchema) == 0 {
+		return nil
+	}
+	for _, block := range e.blocks {
+		if _, ok := d.GetOk(block.name); !ok {
+			continue
+		}
+		// create only blocks are only sent if the service is (re)created
+		if block.createOnly && d.Id() != "" && !d.HasChange(block.name) {
+			continue
+		}
+		var checked, unsupported []string
+		for _, p := range block.parameters {
+			attribute := fmt.Sprintf("%s.0.%s", block.name, p.attribute)
+			// Optional computed parameters are only sent if they are set
+			if _, ok := d.GetOk(attribute); p.schema.Computed && !ok {
+				continue
+			}
+			checked = append(checked, attribute)
+			if _, ok := parametersSchema[p.parameter]; !ok {
+				unsupported = append(unsupported, attribute)
+			}
+		}
+		if len(unsupported) == 0 {
+			continue
+		}
+		templateName := fmt.Sprintf("%s release %s with performance class %s", e.displayName, template.Properties.Release, template.Properties.PerformanceClass)
+		if len(unsupported) == len(checked) {
+			errorMessages = append(errorMessages, fmt.Sprintf("'%s' is not supported by %s\n", block.name, templateName))
+		} else {
+			errorMessages = append(errorMessages, fmt.Sprintf("'%s' is not supported by %s\n", strings.Join(unsupported, "', '"), templateName))
+		}
+	}
+	return errorMessages
+}
+
+// verify runs the checks of the engine after the service has been created or updated
+func (e *paasEngine) verify(ctx context.Context, d *schema.ResourceData) diag.Diagnostics {
+	if e.preflightCheck != nil {
+		return e.preflightCheck.verify(ctx, d)
+	}
+	return nil
+}
+
+func (e *paasEngine) read(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("read %s (%s) resource -", e.logName, d.Id())
+	paas, err := client.GetPaaSService(context.Background(), d.Id())
+	if err != nil {
+		if requestError, ok := err.(gsclient.RequestError); ok {
+			if requestError.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return e.setServiceProperties(d, client, paas, errorPrefix)
+}
+
+func (e *paasEngine) dataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	id := d.Get("resource_id").(string)
+	errorPrefix := fmt.Sprintf("read %s (%s) datasource -", e.logName, id)
+	paas, err := client.GetPaaSService(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	d.SetId(paas.Properties.ObjectUUID)
+
+	// The release and the performance class are only known by the template of the service
+	paasTemplates, err := client.GetPaaSTemplateList(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s error getting templates: %v", errorPrefix, err)
+	}
+	for _, template := range paasTemplates {
+		if template.Properties.ObjectUUID == paas.Properties.ServiceTemplateUUID {
+			if err = d.Set("release", template.Properties.Release); err != nil {
+				return fmt.Errorf("%s error setting release: %v", errorPrefix, err)
+			}
+			if err = d.Set("performance_class", template.Properties.PerformanceClass); err != nil {
+				return fmt.Errorf("%s error setting performance_class: %v", errorPrefix, err)
+			}
+		}
+	}
+	return e.setServiceProperties(d, client, paas, errorPrefix)
+}
+
+// setServiceProperties sets the attributes of the engine from a PaaS service
+func (e *paasEngine) setServiceProperties(d *schema.ResourceData, client *gsclient.Client, paas gsclient.PaaSService, errorPrefix string) error {
+	var err error
+	props := paas.Properties
+	creds := props.Credentials
+	if err = d.Set("name", props.Name); err != nil {
+		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
+	}
+	if len(creds) > 0 {
+		if err = d.Set("username", creds[0].Username); err != nil {
+			return fmt.Errorf("%s error setting username: %v", errorPrefix, err)
+		}
+		if err = d.Set("password", creds[0].Password); err != nil {
+			return fmt.Errorf("%s error setting password: %v", errorPrefix, err)
+		}
+	}
+	if err = d.Set("security_zone_uuid", props.SecurityZoneUUID); err != nil {
+		return fmt.Errorf("%s error setting security_zone_uuid: %v", errorPrefix, err)
+	}
+	if err = d.Set("network_uuid", props.NetworkUUID); err != nil {
+		return fmt.Errorf("%s error setting network_uuid: %v", errorPrefix, err)
+	}
+	if err = d.Set("service_template_uuid", props.ServiceTemplateUUID); err != nil {
+		return fmt.Errorf("%s error setting service_template_uuid: %v", errorPrefix, err)
+	}
+	if err = d.Set("service_template_category", props.ServiceTemplateCategory); err != nil {
+		return fmt.Errorf("%s error setting service_template_category: %v", errorPrefix, err)
+	}
+	if err = d.Set("usage_in_minutes", props.UsageInMinutes); err != nil {
+		return fmt.Errorf("%s error setting usage_in_minutes: %v", errorPrefix, err)
+	}
+	if err = d.Set("change_time", props.ChangeTime.String()); err != nil {
+		return fmt.Errorf("%s error setting change_time: %v", errorPrefix, err)
+	}
+	if err = d.Set("create_time", props.CreateTime.String()); err != nil {
+		return fmt.Errorf("%s error setting create_time: %v", errorPrefix, err)
+	}
+	if err = d.Set("status", props.Status); err != nil {
+		return fmt.Errorf("%s error setting status: %v", errorPrefix, err)
+	}
+
+	// Set the parameters of the engine
+	for _, p := range e.parameters {
+		if err = d.Set(p.attribute, props.Parameters[p.parameter]); err != nil {
+			return fmt.Errorf("%s error setting %s: %v", errorPrefix, p.attribute, err)
+		}
+	}
+	for _, block := range e.blocks {
+		if block.createOnly || len(block.parameters) == 0 || props.Parameters[block.parameters[0].parameter] == nil {
+			continue
+		}
+		values := make(map[string]interface{})
+		for _, p := range block.parameters {
+			values[p.attribute] = props.Parameters[p.parameter]
+		}
+		if err = d.Set(block.name, []interface{}{values}); err != nil {
+			return fmt.Errorf("%s error setting %s: %v", errorPrefix, block.name, err)
+		}
+	}
+
+	//Get listen ports
+	listenPorts := make([]interface{}, 0)
+	for host, value := range props.ListenPorts {
+		for k, portValue := range value {
+			port := map[string]interface{}{
+				"name": k,
+				"host": host,
+				"port": portValue,
+			}
+			listenPorts = append(listenPorts, port)
+		}
+	}
+	if err = d.Set("listen_port", listenPorts); err != nil {
+		return fmt.Errorf("%s error setting listen ports: %v", errorPrefix, err)
+	}
+	if err = setPaaSConnection(d, props, errorPrefix); err != nil {
+		return err
+	}
+
+	//Get core count's limit
+	if e.autoscaling {
+		for _, value := range props.ResourceLimits {
+			if value.Resource == "cores" {
+				if err = d.Set("max_core_count", value.Limit); err != nil {
+					return fmt.Errorf("%s error setting max_core_count: %v", errorPrefix, err)
+				}
+			}
+		}
+	}
+
+	//Set labels
+	if err = d.Set("labels", props.Labels); err != nil {
+		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
+	}
+
+	// Look for security zone's network that the PaaS service is connected to
+	// (if the paas is connected to security zone. O.w skip)
+	if props.SecurityZoneUUID == "" {
+		return nil
+	}
+	networks, err := client.GetNetworkList(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s error getting networks: %v", errorPrefix, err)
+	}
+	//look for a network that the service is in
+	for _, network := range networks {
+		securityZones := network.Properties.Relations.PaaSSecurityZones
+		//Each network can contain only one security zone
+		if len(securityZones) >= 1 {
+			if securityZones[0].ObjectUUID == props.SecurityZoneUUID {
+				if err = d.Set("network_uuid", network.Properties.ObjectUUID); err != nil {
+					return fmt.Errorf("%s error setting network_uuid: %v", errorPrefix, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// serviceParameters returns the parameters of the service which are set by the attributes. The
+// parameters of create only blocks are only returned if the service is created.
+func (e *paasEngine) serviceParameters(d *schema.ResourceData, create bool) map[string]interface{} {
+	params := make(map[string]interface{})
+	for _, p := range e.parameters {
+		if !p.schema.Computed {
+			params[p.parameter] = d.Get(p.attribute)
+		} else if val, ok := d.GetOk(p.attribute); ok {
+			params[p.parameter] = val
+		}
+	}
+	for _, block := range e.blocks {
+		if _, ok := d.GetOk(block.name); !ok || (block.createOnly && !create) {
+			continue
+		}
+		for _, p := range block.parameters {
+			attribute := fmt.Sprintf("%s.0.%s", block.name, p.attribute)
+			if !p.schema.Computed {
+				params[p.parameter] = d.Get(attribute)
+			} else if val, ok := d.GetOk(attribute); ok {
+				params[p.parameter] = val
+			}
+		}
+	}
+	return params
+}
+
+// resourceLimits returns the resource limits of the service which are set by the attributes
+func (e *paasEngine) resourceLimits(d *schema.ResourceData) []gsclient.ResourceLimit {
+	if !e.autoscaling {
+		return nil
+	}
+	if val, ok := d.GetOk("max_core_count"); ok {
+		return []gsclient.ResourceLimit{
+			{
+				Resource: "cores",
+				Limit:    val.(int),
+			},
+		}
+	}
+	return nil
+}
+
+// templateUUID returns the UUID of the template of the engine with the release and the
+// performance class of the resource
+func (e *paasEngine) templateUUID(client *gsclient.Client, d *schema.ResourceData) (string, error) {
+	template, err := e.findTemplate(context.Background(), client, d.Get("release").(string), d.Get("performance_class").(string))
+	if err != nil {
+		return "", err
+	}
+	return template.Properties.ObjectUUID, nil
+}
+
+func (e *paasEngine) create(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("create %s (%s) resource -", e.logName, d.Id())
+
+	templateUUID, err := e.templateUUID(client, d)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+
+	requestBody := gsclient.PaaSServiceCreateRequest{
+		Name:                    d.Get("name").(string),
+		PaaSServiceTemplateUUID: templateUUID,
+		Labels:                  convSOStrings(d.Get("labels").(*schema.Set).List()),
+		ResourceLimits:          e.resourceLimits(d),
+		Parameters:              e.serviceParameters(d, true),
+	}
+	paasPasswordParameters(d, requestBody.Parameters)
+	networkUUIDInf, isNetworkSet := d.GetOk("network_uuid")
+	if isNetworkSet {
+		requestBody.NetworkUUID = networkUUIDInf.(string)
+	}
+	// If network_uuid is set, skip setting security_zone_uuid.
+	if secZoneUUIDInf, ok := d.GetOk("security_zone_uuid"); ok && !isNetworkSet {
+		requestBody.PaaSSecurityZoneUUID = secZoneUUIDInf.(string)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	response, err := client.CreatePaaSService(ctx, requestBody)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	d.SetId(response.ObjectUUID)
+	log.Printf("The id for %s %s has been set to %v", e.serviceName, requestBody.Name, response.ObjectUUID)
+	if err = rotatePaaSCredentials(ctx, client, d, true); err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	return e.read(d, meta)
+}
+
+func (e *paasEngine) update(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("update %s (%s) resource -", e.logName, d.Id())
+
+	// The service does not need to be updated if only provider settings changed
+	settings := make([]string, 0, len(e.settings))
+	for key := range e.settings {
+		settings = append(settings, key)
+	}
+	if !d.HasChangesExcept(settings...) {
+		return e.read(d, meta)
+	}
+
+	labels := convSOStrings(d.Get("labels").(*schema.Set).List())
+	requestBody := gsclient.PaaSServiceUpdateRequest{
+		Name:           d.Get("name").(string),
+		Labels:         &labels,
+		ResourceLimits: e.resourceLimits(d),
+		Parameters:     e.serviceParameters(d, false),
+	}
+	paasPasswordParameters(d, requestBody.Parameters)
+	if d.HasChange("network_uuid") {
+		requestBody.NetworkUUID = d.Get("network_uuid").(string)
+	}
+	// Only update templateUUID, when `release` or `performance_class` is changed
+	if d.HasChange("release") || d.HasChange("performance_class") {
+		templateUUID, err := e.templateUUID(client, d)
+		if err != nil {
+			return fmt.Errorf("%s error: %v", errorPrefix, err)
+		}
+		requestBody.PaaSServiceTemplateUUID = templateUUID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	err := client.UpdatePaaSService(ctx, d.Id(), requestBody)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	if err = rotatePaaSCredentials(ctx, client, d, false); err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	return e.read(d, meta)
+}
+
+func (e *paasEngine) delete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("delete %s (%s) resource -", e.logName, d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	err := errHandler.SuppressHTTPErrorCodes(
+		client.DeletePaaSService(ctx, d.Id()),
+		http.StatusNotFound,
+	)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package gridscale
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const defaultBackupServerURL = "https://gos3.io/"
+
+var mariaDBEngine = paasEngine{
+	name:        "gridscale_mariadb",
+	flavour:     "mariadb",
+	displayName: "MariaDB",
+	serviceName: "MariaDB service",
+	logName:     "mariadb",
+	autoscaling: true,
+	blocks:      []paasEngineBlock{s3BackupBlock, restoreFromBlock},
+	parameters:  mySQLFamilyParameters("mariadb_", "NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION,STRICT_TRANS_TABLES,ERROR_FOR_DIVISION_BY_ZERO", "MIXED"),
+}
+
+var mySQLEngine = paasEngine{
+	name:        "gridscale_mysql",
+	flavour:     "mysql",
+	displayName: "MySQL",
+	serviceName: "MySQL service",
+	logName:     "mysql",
+	autoscaling: true,
+	blocks:      []paasEngineBlock{s3BackupBlock, restoreFromBlock},
+	parameters:  mySQLFamilyParameters("mysql_", "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION", "ROW"),
+}
+
+var mySQL8_0Engine = paasEngine{
+	name:        "gridscale_mysql8_0",
+	flavour:     "mysql",
+	displayName: "MySQL",
+	serviceName: "MySQL service",
+	logName:     "mysql",
+	autoscaling: true,
+	blocks:      []paasEngineBlock{s3BackupBlock, restoreFromBlock},
+	parameters: []paasEngineParameter{
+		{
+			attribute: "mysql_sql_mode",
+			parameter: "mysql_sql_mode",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "SQL Mode.",
+				Optional:    true,
+				Default:     "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION",
+			},
+		},
+		{
+			attribute: "mysql_max_connections",
+			parameter: "mysql_max_connections",
+			schema: &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Max Connections.",
+				Optional:    true,
+				Default:     4000,
+			},
+		},
+		{
+			attribute: "mysql_default_time_zone",
+			parameter: "mysql_default_time_zone",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Server Timezone.",
+				Optional:    true,
+				Default:     "UTC",
+			},
+		},
+		{
+			attribute: "mysql_max_allowed_packet",
+			parameter: "mysql_max_allowed_packet",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Max Allowed Packet Size. Format: xM (where x is an integer, M stands for unit: k(kB), M(MB), G(GB)).",
+				Optional:    true,
+				Default:     "64M",
+			},
+		},
+	},
+}
+
+// postgreSQLAuditLogPreflightCheck checks the bucket of the pgAudit logs
+var postgreSQLAuditLogPreflightCheck = s3PreflightCheck{
+	description: "pgAudit log",
+	enabled:     "pgaudit_log_preflight_check",
+	endpoint:    "pgaudit_log_server_url",
+	bucket:      "pgaudit_log_bucket",
+	accessKey:   "pgaudit_log_access_key",
+	secretKey:   "pgaudit_log_secret_key",
+}
+
+var postgreSQLEngine = paasEngine{
+	name:               "gridscale_postgresql",
+	flavour:            "postgres",
+	displayName:        "PostgreSQL",
+	serviceName:        "PostgreSQL service",
+	logName:            "postgresql",
+	performanceClasses: postgreSQLPerformanceClasses,
+	autoscaling:        true,
+	blocks:             []paasEngineBlock{s3BackupBlock, restoreFromBlock},
+	parameters: []paasEngineParameter{
+		{
+			attribute: "pgaudit_log_bucket",
+			parameter: "pgaudit_log_bucket",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Object Storage bucket to upload audit logs to. For pgAudit to be enabled these additional parameters need to be configured: pgaudit_log_server_url, pgaudit_log_access_key, pgaudit_log_secret_key.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+		{
+			attribute: "pgaudit_log_server_url",
+			parameter: "pgaudit_log_server_url",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Object Storage server URL the bucket is located on.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+		{
+			attribute: "pgaudit_log_access_key",
+			parameter: "pgaudit_log_access_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Access key used to authenticate against Object Storage server.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+		{
+			attribute: "pgaudit_log_secret_key",
+			parameter: "pgaudit_log_secret_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Secret key used to authenticate against Object Storage server.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+		{
+			attribute: "pgaudit_log_rotation_frequency",
+			parameter: "pgaudit_log_rotation_frequency",
+			schema: &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Rotation (in minutes) for audit logs. Logs are uploaded to Object Storage once rotated.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	},
+	settings: map[string]*schema.Schema{
+		"pgaudit_log_preflight_check": {
+			Type:        schema.TypeBool,
+			Description: "Check at plan time and after apply that the audit log bucket exists and the keys can write to it.",
+			Optional:    true,
+			Default:     false,
+		},
+	},
+	validateParameters: func(d *schema.ResourceDiff, v *paasParameterValidation) {
+		if logServerURL, ok := d.GetOk("pgaudit_log_server_url"); ok {
+			if _, err := url.ParseRequestURI(logServerURL.(string)); err != nil {
+				v.addError("Invalid 'pgaudit_log_server_url' value, doesn't match URL format\n")
+			}
+		}
+	},
+	preflightCheck: &postgreSQLAuditLogPreflightCheck,
+}
+
+var msSQLServerEngine = paasEngine{
+	name:               "gridscale_sqlserver",
+	flavour:            "mssql",
+	displayName:        "MS SQL Server",
+	serviceName:        "MS SQL Server",
+	logName:            "mssql",
+	performanceClasses: msSQLServerPerformanceClasses,
+	blocks:             []paasEngineBlock{s3BackupBlock, restoreFromBlock},
+}
+
+var redisStoreEngine = paasEngine{
+	name:        "gridscale_redis_store",
+	flavour:     "redis-store",
+	displayName: "Redis store",
+	serviceName: "Redis store service",
+	logName:     "redis store",
+}
+
+var redisCacheEngine = paasEngine{
+	name:        "gridscale_redis_cache",
+	flavour:     "redis-cache",
+	displayName: "Redis cache",
+	serviceName: "Redis cache service",
+	logName:     "redis cache",
+}
+
+var memcachedEngine = paasEngine{
+	name:               "gridscale_memcached",
+	flavour:            "memcached",
+	displayName:        "Memcached",
+	serviceName:        "Memcached service",
+	logName:            "memcached",
+	deprecationMessage: "This resource is deprecated.",
+	autoscaling:        true,
+}
+
+// s3BackupBlock configures the backups of SQL services to an Object Storage bucket
+var s3BackupBlock = paasEngineBlock{
+	name:        "s3_backup",
+	description: "Allow backup/restore of the service to/from a S3 bucket.",
+	parameters: []paasEngineParameter{
+		{
+			attribute: "backup_bucket",
+			parameter: "backup_bucket",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Object Storage bucket to upload backups to and restore backups from.",
+			},
+		},
+		{
+			attribute: "backup_retention",
+			parameter: "backup_retention",
+			schema: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Retention (in seconds) for local originals of backups. (0 for immediate removal once uploaded to Object Storage (default), higher values for delayed removal after the given time and once uploaded to Object Storage).",
+			},
+		},
+		{
+			attribute: "backup_access_key",
+			parameter: "backup_access_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Access key used to authenticate against Object Storage server.",
+			},
+		},
+		{
+			attribute: "backup_secret_key",
+			parameter: "backup_secret_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Secret key used to authenticate against Object Storage server.",
+			},
+		},
+		{
+			attribute: "backup_server_url",
+			parameter: "backup_server_url",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultBackupServerURL,
+				ValidateFunc: validateBackupServerURL,
+				Description:  "Object Storage server URL the bucket is located on.",
+			},
+		},
+		{
+			attribute: "backup_schedule",
+			parameter: "backup_schedule",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Time of the day (UTC, format HH:MM) at which the daily backup is created.",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`),
+					"must be a time of the day in the format HH:MM"),
+			},
+		},
+	},
+}
+
+// restoreFromBlock creates a SQL service from a backup in an Object Storage bucket
+var restoreFromBlock = paasEngineBlock{
+	name:        "restore_from",
+	description: "Create the service from a backup in an Object Storage bucket. Changing the block replaces the service.",
+	createOnly:  true,
+	parameters: []paasEngineParameter{
+		{
+			attribute: "backup_object",
+			parameter: "restore_backup_object",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Name of the backup object to restore.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+		},
+		{
+			attribute: "bucket",
+			parameter: "restore_bucket",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Object Storage bucket the backup is located in.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+		},
+		{
+			attribute: "access_key",
+			parameter: "restore_access_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Access key used to authenticate against Object Storage server.",
+			},
+		},
+		{
+			attribute: "secret_key",
+			parameter: "restore_secret_key",
+			schema: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Secret key used to authenticate against Object Storage server.",
+			},
+		},
+		{
+			attribute: "server_url",
+			parameter: "restore_server_url",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultBackupServerURL,
+				ValidateFunc: validateBackupServerURL,
+				Description:  "Object Storage server URL the bucket is located on.",
+			},
+		},
+	},
+}
+
+// validateBackupServerURL checks that the backups are stored on a supported Object Storage server
+func validateBackupServerURL(v interface{}, k string) (ws []string, errors []error) {
+	if v.(string) != defaultBackupServerURL {
+		errors = append(errors, fmt.Errorf("currently, only %s is supported", defaultBackupServerURL))
+	}
+	return
+}
+
+// mySQLFamilyParameters returns the parameters shared by MariaDB and MySQL 5.7. The engines
+// only differ in the prefix of the parameters and some defaults.
+func mySQLFamilyParameters(prefix, sqlMode, binlogFormat string) []paasEngineParameter {
+	parameters := []struct {
+		name string
+		s    schema.Schema
+	}{
+		{"log_bin", schema.Schema{Type: schema.TypeBool, Description: "Binary Logging.", Default: false}},
+		{"sql_mode", schema.Schema{Type: schema.TypeString, Description: "SQL Mode.", Default: sqlMode}},
+		{"server_id", schema.Schema{Type: schema.TypeInt, Description: "Server Id.", Default: 1}},
+		{"query_cache", schema.Schema{Type: schema.TypeBool, Description: "Enable query cache.", Default: true}},
+		{"binlog_format", schema.Schema{Type: schema.TypeString, Description: "Binary Logging Format.", Default: binlogFormat}},
+		{"max_connections", schema.Schema{Type: schema.TypeInt, Description: "Max Connections.", Default: 4000}},
+		{"query_cache_size", schema.Schema{Type: schema.TypeString, Description: "Query Cache Size. Format: xM (where x is an integer, M stands for unit: k(kB), M(MB), G(GB)).", Default: "128M"}},
+		{"default_time_zone", schema.Schema{Type: schema.TypeString, Description: "Server Timezone.", Default: "UTC"}},
+		{"query_cache_limit", schema.Schema{Type: schema.TypeString, Description: "Query Cache Limit. Format: xM (where x is an integer, M stands for unit: k(kB), M(MB), G(GB)).", Default: "1M"}},
+		{"max_allowed_packet", schema.Schema{Type: schema.TypeString, Description: "Max Allowed Packet Size. Format: xM (where x is an integer, M stands for unit: k(kB), M(MB), G(GB)).", Default: "64M"}},
+	}
+	var result []paasEngineParameter
+	for _, p := range parameters {
+		s := p.s
+		s.Optional = true
+		result = append(result, paasEngineParameter{
+			attribute: prefix + p.name,
+			parameter: prefix + p.name,
+			schema:    &s,
+		})
+	}
+	return result
+}
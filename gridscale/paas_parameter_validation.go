@@ -0,0 +1,80 @@
+package gridscale
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	paasu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/paas-utils"
+)
+
+// paasParameterValidation collects the PaaS service parameters requested by a resource and
+// validates them against the parameters schema of the PaaS template.
+type paasParameterValidation struct {
+	params         map[string]interface{}
+	current        map[string]interface{}
+	attributePaths map[string]string
+	errorMessages  []string
+}
+
+// newPaaSParameterValidation collects the parameters set by the attributes of a resource.
+// The keys of attributes are the attribute paths (e.g. "s3_backup.0.backup_bucket"), the values
+// are the names of the parameters. Attribute paths containing "*" (list indices) are only used
+// to name nested parameters in the error messages.
+func newPaaSParameterValidation(d *schema.ResourceDiff, attributes map[string]string) *paasParameterValidation {
+	v := &paasParameterValidation{
+		params:         make(map[string]interface{}),
+		current:        make(map[string]interface{}),
+		attributePaths: make(map[string]string),
+	}
+	for attribute, parameter := range attributes {
+		v.attributePaths[parameter] = attribute
+		if strings.Contains(attribute, "*") || !d.NewValueKnown(attribute) {
+			continue
+		}
+		if value, ok := d.GetOk(attribute); ok {
+			v.params[parameter] = paasParameterValue(value)
+		}
+		// The current value is needed to check immutable parameters
+		if d.Id() != "" && d.HasChange(attribute) {
+			if old, _ := d.GetChange(attribute); old != nil && !reflect.ValueOf(paasParameterValue(old)).IsZero() {
+				v.current[parameter] = paasParameterValue(old)
+			}
+		}
+	}
+	return v
+}
+
+// set sets a parameter which is not set by a single attribute (e.g. converted or nested values)
+func (v *paasParameterValidation) set(parameter string, value interface{}) {
+	v.params[parameter] = value
+}
+
+// addError adds an error message of a check which is not covered by the parameters schema
+func (v *paasParameterValidation) addError(message string) {
+	v.errorMessages = append(v.errorMessages, message)
+}
+
+// validate validates the parameters against the parameters schema of the template and
+// returns all error messages as one error
+func (v *paasParameterValidation) validate(template gsclient.PaaSTemplate, opts paasu.Options) error {
+	opts.AttributePaths = v.attributePaths
+	if opts.Current == nil {
+		opts.Current = v.current
+	}
+	errorMessages := append(v.errorMessages, paasu.ValidateParameters(template.Properties.ParametersSchema, v.params, opts)...)
+	if len(errorMessages) != 0 {
+		return errors.New(strings.Join(errorMessages, ""))
+	}
+	return nil
+}
+
+// paasParameterValue converts the value of an attribute to the value of a parameter
+func paasParameterValue(value interface{}) interface{} {
+	if set, ok := value.(*schema.Set); ok {
+		return set.List()
+	}
+	return value
+}
@@ -14,7 +14,7 @@ var (
 )
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"uuid": {
 				Type:        schema.TypeString,
@@ -62,17 +62,24 @@ func Provider() *schema.Provider {
 			"gridscale_ipv6":                     dataSourceGridscaleIpv6(),
 			"gridscale_sshkey":                   dataSourceGridscaleSshkey(),
 			"gridscale_template":                 dataSourceGridscaleTemplate(),
+			"gridscale_templates":                dataSourceGridscaleTemplates(),
 			"gridscale_loadbalancer":             dataSourceGridscaleLoadBalancer(),
 			"gridscale_snapshot":                 dataSourceGridscaleStorageSnapshot(),
 			"gridscale_backup_list":              dataSourceGridscaleStorageBackupList(),
+			"gridscale_backup_locations":         dataSourceGridscaleBackupLocations(),
+			"gridscale_storage_backup":           dataSourceGridscaleStorageBackup(),
 			"gridscale_snapshotschedule":         dataSourceGridscaleStorageSnapshotSchedule(),
 			"gridscale_backupschedule":           dataSourceGridscaleStorageBackupSchedule(),
 			"gridscale_paas":                     dataSourceGridscalePaaS(),
 			"gridscale_paas_securityzone":        dataSourceGridscalePaaSSecurityZone(),
 			"gridscale_k8s":                      dataSourceGridscaleK8s(),
+			"gridscale_k8s_credentials":          dataSourceGridscaleK8sCredentials(),
+			"gridscale_k8s_versions":             dataSourceGridscaleK8sVersions(),
 			"gridscale_object_storage_accesskey": dataSourceGridscaleObjectStorage(),
 			"gridscale_isoimage":                 dataSourceGridscaleISOImage(),
 			"gridscale_firewall":                 dataSourceGridscaleFirewall(),
+			"gridscale_firewall_rule_set":        dataSourceGridscaleFirewallRuleSet(),
+			"gridscale_firewall_rules_from_text": dataSourceGridscaleFirewallRulesFromText(),
 			"gridscale_marketplace_application":  dataSourceGridscaleMarketplaceApplication(),
 			"gridscale_ssl_certificate":          dataSourceGridscaleSSLCert(),
 		},
@@ -86,23 +93,19 @@ func Provider() *schema.Provider {
 			"gridscale_ipv6":                           resourceGridscaleIpv6(),
 			"gridscale_sshkey":                         resourceGridscaleSshkey(),
 			"gridscale_loadbalancer":                   resourceGridscaleLoadBalancer(),
+			"gridscale_loadbalancer_traffic_split":     resourceGridscaleLoadBalancerTrafficSplit(),
 			"gridscale_snapshot":                       resourceGridscaleStorageSnapshot(),
 			"gridscale_snapshotschedule":               resourceGridscaleStorageSnapshotSchedule(),
+			"gridscale_snapshot_retention_policy":      resourceGridscaleSnapshotRetentionPolicy(),
 			"gridscale_backupschedule":                 resourceGridscaleStorageBackupSchedule(),
 			"gridscale_paas":                           resourceGridscalePaaS(),
-			"gridscale_redis_store":                    resourceGridscaleRedisStore(),
-			"gridscale_redis_cache":                    resourceGridscaleRedisCache(),
 			"gridscale_k8s":                            resourceGridscaleK8s(),
+			"gridscale_k8s_node_pool":                  resourceGridscaleK8sNodePool(),
 			"gridscale_paas_securityzone":              resourceGridscalePaaSSecurityZone(),
-			"gridscale_postgresql":                     resourceGridscalePostgreSQL(),
-			"gridscale_sqlserver":                      resourceGridscaleMSSQLServer(),
-			"gridscale_mysql":                          resourceGridscaleMySQL(),
-			"gridscale_mysql8_0":                       resourceGridscaleMySQL8_0(),
-			"gridscale_mariadb":                        resourceGridscaleMariaDB(),
-			"gridscale_memcached":                      resourceGridscaleMemcached(),
 			"gridscale_filesystem":                     resourceGridscaleFilesystem(),
 			"gridscale_object_storage_accesskey":       resourceGridscaleObjectStorage(),
 			"gridscale_template":                       resourceGridscaleTemplate(),
+			"gridscale_image_build":                    resourceGridscaleImageBuild(),
 			"gridscale_isoimage":                       resourceGridscaleISOImage(),
 			"gridscale_firewall":                       resourceGridscaleFirewall(),
 			"gridscale_marketplace_application":        resourceGridscaleMarketplaceApplication(),
@@ -113,6 +116,12 @@ func Provider() *schema.Provider {
 
 		ConfigureFunc: providerConfigure,
 	}
+	// Register the resources and data sources of the PaaS service engines
+	for _, engine := range paasEngines {
+		provider.ResourcesMap[engine.name] = engine.resource()
+		provider.DataSourcesMap[engine.name] = engine.dataSource()
+	}
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
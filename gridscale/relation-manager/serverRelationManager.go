@@ -132,12 +132,15 @@ func (c *ServerRelationManger) LinkNetworks(ctx context.Context) error {
 			var customFwRulesPtr *gsclient.FirewallRules
 			network := value.(map[string]interface{})
 			//Read custom firewall rules from `network` property (field)
-			customFwRules := readCustomFirewallRules(network)
+			customFwRules, err := readCustomFirewallRules(network)
+			if err != nil {
+				return fmt.Errorf("error reading firewall rules of network (%s): %v", network["object_uuid"], err)
+			}
 			// if customFwRules is not empty, customFwRulesPtr is not nil (fw is active)
 			if !reflect.DeepEqual(customFwRules, gsclient.FirewallRules{}) {
 				customFwRulesPtr = &customFwRules
 			}
-			err := client.LinkNetwork(
+			err = client.LinkNetwork(
 				ctx,
 				d.Id(),
 				network["object_uuid"].(string),
@@ -181,9 +184,10 @@ func (c *ServerRelationManger) LinkNetworks(ctx context.Context) error {
 	return nil
 }
 
-// readCustomFirewallRules reads custom firewall rules from a specific network
+// readCustomFirewallRules reads custom firewall rules from a specific network,
+// merged with the rule sets of the network.
 // returns `gsclient.FirewallRules` type variable
-func readCustomFirewallRules(netData map[string]interface{}) gsclient.FirewallRules {
+func readCustomFirewallRules(netData map[string]interface{}) (gsclient.FirewallRules, error) {
 	//Init firewall rule variable
 	var fwRules gsclient.FirewallRules
 
@@ -218,16 +222,32 @@ func readCustomFirewallRules(netData map[string]interface{}) gsclient.FirewallRu
 
 		//Based on rule type to place the rules in the right property of fwRules variable
 		if ruleType == "rules_v4_in" {
-			fwRules.RulesV4In = fwu.AddDefaultFirewallInboundRules(rules, false) // add default rules
+			fwRules.RulesV4In = rules
 		} else if ruleType == "rules_v4_out" {
 			fwRules.RulesV4Out = rules
 		} else if ruleType == "rules_v6_in" {
-			fwRules.RulesV6In = fwu.AddDefaultFirewallInboundRules(rules, true) // add default rules
+			fwRules.RulesV6In = rules
 		} else if ruleType == "rules_v6_out" {
 			fwRules.RulesV6Out = rules
 		}
 	}
-	return fwRules
+
+	//Merge rule sets (if there are some) after the declared rules
+	if ruleSetsAttr, ok := netData["rule_sets"]; ok {
+		var ruleSetStrs []string
+		for _, ruleSet := range ruleSetsAttr.([]interface{}) {
+			ruleSetStrs = append(ruleSetStrs, ruleSet.(string))
+		}
+		ruleSets, err := fwu.DecodeRuleSets(ruleSetStrs)
+		if err != nil {
+			return gsclient.FirewallRules{}, err
+		}
+		fwRules, _ = fwu.MergeRuleSets(fwRules, ruleSets)
+	}
+
+	fwRules.RulesV4In = fwu.AddDefaultFirewallInboundRules(fwRules.RulesV4In, false) // add default rules
+	fwRules.RulesV6In = fwu.AddDefaultFirewallInboundRules(fwRules.RulesV6In, true)  // add default rules
+	return fwRules, nil
 }
 
 // IsShutdownRequired checks if server is needed to be shutdown when updating
@@ -364,8 +384,11 @@ func (c *ServerRelationManger) UpdateNetRelsProperties(ctx context.Context) erro
 	for idx, networkIntf := range networkListIntf {
 		network := networkIntf.(map[string]interface{})
 		//Read custom firewall rules from `network` property (field)
-		customFwRules := readCustomFirewallRules(network)
-		err := client.UpdateServerNetwork(
+		customFwRules, err := readCustomFirewallRules(network)
+		if err != nil {
+			return fmt.Errorf("error reading firewall rules of network (%s): %v", network["object_uuid"], err)
+		}
+		err = client.UpdateServerNetwork(
 			ctx,
 			d.Id(),
 			network["object_uuid"].(string),
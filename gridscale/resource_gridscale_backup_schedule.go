@@ -15,7 +15,7 @@ import (
 )
 
 func resourceGridscaleStorageBackupSchedule() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceGridscaleBackupScheduleCreate,
 		Read:   resourceGridscaleBackupScheduleRead,
 		Delete: resourceGridscaleBackupScheduleDelete,
@@ -23,6 +23,7 @@ func resourceGridscaleStorageBackupSchedule() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeScheduleCronDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -30,9 +31,10 @@ func resourceGridscaleStorageBackupSchedule() *schema.Resource {
 				Description: "The human-readable name of the object",
 			},
 			"next_runtime": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The date and time that the storage backup schedule will be run. Format: \"2006-01-02 15:04:05\"",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The date and time that the storage backup schedule will be run. Format: \"2006-01-02 15:04:05\"",
+				AtLeastOneOf: []string{"next_runtime", "cron"},
 			},
 			"next_runtime_computed": {
 				Type:        schema.TypeString,
@@ -47,9 +49,11 @@ func resourceGridscaleStorageBackupSchedule() *schema.Resource {
 			},
 			"run_interval": {
 				Type:         schema.TypeInt,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.IntAtLeast(60),
 				Description:  "The interval at which the schedule will run (in minutes)",
+				AtLeastOneOf: []string{"run_interval", "cron"},
 			},
 			"storage_uuid": {
 				Type:        schema.TypeString,
@@ -117,6 +121,8 @@ func resourceGridscaleStorageBackupSchedule() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 	}
+	addScheduleCronSchema(r.Schema)
+	return r
 }
 
 func resourceGridscaleBackupScheduleRead(d *schema.ResourceData, meta interface{}) error {
@@ -192,10 +198,15 @@ func resourceGridscaleBackupScheduleCreate(d *schema.ResourceData, meta interfac
 		Active:             d.Get("active").(bool),
 		BackupLocationUUID: d.Get("backup_location_uuid").(string),
 	}
-	nextRuntime, err := time.Parse(timeLayout, d.Get("next_runtime").(string))
+	nextRuntime, runInterval, isCron, err := scheduleCronRuntime(d, time.Now())
 	if err != nil {
 		return err
 	}
+	if isCron {
+		requestBody.RunInterval = runInterval
+	} else if nextRuntime, err = time.Parse(timeLayout, d.Get("next_runtime").(string)); err != nil {
+		return err
+	}
 	requestBody.NextRuntime = gsclient.GSTime{Time: nextRuntime}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
@@ -226,17 +237,26 @@ func resourceGridscaleBackupScheduleUpdate(d *schema.ResourceData, meta interfac
 	// This is a bad behavior. Because if next_runtime_computed is different from
 	// next_runtime (since it might be changed outside of tf), and next_runtime is not changed (by the user);
 	// tf should not put the "old" next_runtime to the update request.
-	if d.HasChange("next_runtime") {
+	if d.HasChange("next_runtime") && d.Get("next_runtime").(string) != "" {
 		nextRuntime, err := time.Parse(timeLayout, d.Get("next_runtime").(string))
 		if err != nil {
 			return fmt.Errorf("%s error: %v", errorPrefix, err)
 		}
 		requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
 	}
+	// The runtime of a cron expression is always sent, as it is only updated when it drifted
+	nextRuntime, runInterval, isCron, err := scheduleCronRuntime(d, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	if isCron {
+		requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
+		requestBody.RunInterval = runInterval
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
-	err := client.UpdateStorageBackupSchedule(ctx, storageUUID, d.Id(), requestBody)
+	err = client.UpdateStorageBackupSchedule(ctx, storageUUID, d.Id(), requestBody)
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,9 +15,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// s3PreflightCheckTimeout is the timeout of the pre-flight check of buckets
+const s3PreflightCheckTimeout = 1 * time.Minute
+
 type gridscaleS3Provider struct {
 	AccessKey, SecretKey string
 }
@@ -380,3 +386,134 @@ func initS3Client(provider *gridscaleS3Provider, s3host string) *s3.Client {
 
 	return client
 }
+
+// initS3ClientForEndpoint creates an S3 client for an Object Storage endpoint, which is either a
+// host (e.g. gos3.io) or a URL (e.g. https://gos3.io). The host of the endpoint is returned as well.
+func initS3ClientForEndpoint(endpoint, accessKey, secretKey string) (*s3.Client, string) {
+	s3Host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		s3Host = u.Host
+	}
+	return initS3Client(&gridscaleS3Provider{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}, s3Host), s3Host
+}
+
+// isS3NotFoundError reports whether an S3 error means that a bucket or object does not exist
+func isS3NotFoundError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchBucket":
+			return true
+		}
+	}
+	return false
+}
+
+// s3PreflightCheck describes the attributes of a resource which configure an Object Storage bucket
+// the platform writes to (e.g. for log delivery). The fields are the names of the attributes.
+type s3PreflightCheck struct {
+	description string
+	enabled     string
+	endpoint    string
+	bucket      string
+	accessKey   string
+	secretKey   string
+}
+
+// run checks if the bucket exists and the keys can write objects to it. get returns the value of an
+// attribute and whether it is set and known. The check is skipped, if it is not enabled or not all
+// attributes are set and known.
+func (c s3PreflightCheck) run(ctx context.Context, get func(key string) (interface{}, bool)) error {
+	if enabled, ok := get(c.enabled); !ok || !enabled.(bool) {
+		return nil
+	}
+	values := make(map[string]string)
+	for _, key := range []string{c.endpoint, c.bucket, c.accessKey, c.secretKey} {
+		value, ok := get(key)
+		if !ok || value.(string) == "" {
+			return nil
+		}
+		values[key] = value.(string)
+	}
+	err := checkS3BucketWriteAccess(ctx, values[c.endpoint], values[c.bucket], values[c.accessKey], values[c.secretKey])
+	if err != nil {
+		return fmt.Errorf("S3 pre-flight check of %s failed: %v. Please check %s, %s, %s and %s",
+			c.description, err, c.endpoint, c.bucket, c.accessKey, c.secretKey)
+	}
+	return nil
+}
+
+// validateDiff runs the check at plan time, if the resource is created or one of the attributes changed
+func (c s3PreflightCheck) validateDiff(ctx context.Context, d *schema.ResourceDiff) error {
+	if d.Id() != "" && !d.HasChanges(c.enabled, c.endpoint, c.bucket, c.accessKey, c.secretKey) {
+		return nil
+	}
+	return c.run(ctx, func(key string) (interface{}, bool) {
+		if !d.NewValueKnown(key) {
+			return nil, false
+		}
+		return d.GetOk(key)
+	})
+}
+
+// verify runs the check after the resource has been created or one of the attributes changed. As the
+// resource exists already, a failed check is reported as a warning, so that the resource is not tainted.
+func (c s3PreflightCheck) verify(ctx context.Context, d *schema.ResourceData) diag.Diagnostics {
+	if !d.IsNewResource() && !d.HasChanges(c.enabled, c.endpoint, c.bucket, c.accessKey, c.secretKey) {
+		return nil
+	}
+	if err := c.run(ctx, d.GetOk); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The %s bucket is not writable", c.description),
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+// checkS3BucketWriteAccess checks if the bucket exists on the Object Storage endpoint and the keys can
+// write objects to it, by writing and deleting a probe object.
+func checkS3BucketWriteAccess(ctx context.Context, endpoint, bucket, accessKey, secretKey string) error {
+	s3Client, s3Host := initS3ClientForEndpoint(endpoint, accessKey, secretKey)
+
+	ctx, cancel := context.WithTimeout(ctx, s3PreflightCheckTimeout)
+	defer cancel()
+	if _, err := s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return describeS3Error(err, bucket, s3Host)
+	}
+	key := fmt.Sprintf(".gridscale-preflight-check-%d", time.Now().UnixNano())
+	_, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   strings.NewReader("gridscale terraform provider pre-flight check"),
+	})
+	if err != nil {
+		return describeS3Error(err, bucket, s3Host)
+	}
+	if _, err = s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+		log.Printf("[WARN] the probe object %s could not be deleted from bucket %s: %v", key, bucket, err)
+	}
+	return nil
+}
+
+// describeS3Error converts the errors of the pre-flight check to actionable messages
+func describeS3Error(err error, bucket, s3Host string) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchBucket":
+			return fmt.Errorf("bucket %q does not exist on %s", bucket, s3Host)
+		case "InvalidAccessKeyId":
+			return fmt.Errorf("the access key is unknown to %s", s3Host)
+		case "SignatureDoesNotMatch":
+			return fmt.Errorf("the secret key does not match the access key")
+		case "Forbidden", "AccessDenied":
+			return fmt.Errorf("the keys are invalid or not allowed to write to bucket %q on %s", bucket, s3Host)
+		}
+	}
+	return fmt.Errorf("error accessing bucket %q on %s: %v", bucket, s3Host, err)
+}
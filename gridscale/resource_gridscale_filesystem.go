@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+	paasu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/paas-utils"
 
 	"log"
 )
@@ -58,6 +59,7 @@ func resourceGridscaleFilesystem() *schema.Resource {
 				}
 				return nil
 			}),
+			validateFilesystemParameters,
 		),
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -408,6 +410,36 @@ func resourceGridscaleFilesystemDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// filesystemParameterAttributes maps the attributes of the filesystem resource to the parameters of the service
+var filesystemParameterAttributes = map[string]string{
+	"root_squash":       "root_squash",
+	"allowed_ip_ranges": "allowed_ip_ranges",
+	"anon_uid":          "anon_uid",
+	"anon_gid":          "anon_gid",
+}
+
+// validateFilesystemParameters validates the filesystem parameters against the template of the
+// requested release and performance class.
+func validateFilesystemParameters(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("release") || !d.NewValueKnown("performance_class") {
+		return nil
+	}
+	client := meta.(*gsclient.Client)
+	paasTemplates, err := client.GetPaaSTemplateList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, template := range paasTemplates {
+		if template.Properties.Flavour == filesystemTemplateFlavourName &&
+			template.Properties.Release == d.Get("release").(string) &&
+			template.Properties.PerformanceClass == d.Get("performance_class").(string) {
+			return newPaaSParameterValidation(d, filesystemParameterAttributes).validate(template, paasu.Options{})
+		}
+	}
+	// an invalid release is reported by the release validation
+	return nil
+}
+
 // getFilesystemTemplateUUID returns the UUID of the filesystem service template.
 func getFilesystemTemplateUUID(client *gsclient.Client, release, performanceClass string) (string, error) {
 	paasTemplates, err := client.GetPaaSTemplateList(context.Background())
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"reflect"
 	"time"
 
 	fwu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/firewall-utils"
@@ -25,6 +26,28 @@ func resourceGridscaleFirewall() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if !firewallRuleSetsKnown(d, "rule_sets") {
+				return d.SetNewComputed("rule_set_conflicts")
+			}
+			for _, key := range []string{"rules_v4_in", "rules_v4_out", "rules_v6_in", "rules_v6_out"} {
+				if !d.NewValueKnown(key) {
+					return d.SetNewComputed("rule_set_conflicts")
+				}
+			}
+			_, conflicts, err := mergeFirewallRuleSets(d)
+			if err != nil {
+				return err
+			}
+			oldConflicts := convSOStrings(d.Get("rule_set_conflicts").([]interface{}))
+			if len(oldConflicts) == 0 && len(conflicts) == 0 {
+				return nil
+			}
+			if !reflect.DeepEqual(oldConflicts, conflicts) {
+				return d.SetNew("rule_set_conflicts", conflicts)
+			}
+			return nil
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -63,6 +86,21 @@ func resourceGridscaleFirewall() *schema.Resource {
 					Schema: getFirewallRuleCommonSchema(),
 				},
 			},
+			"rule_sets": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rule sets (rule_set attribute of gridscale_firewall_rule_set data sources) which are merged, in sequence, after the rules declared in rules_v4_in, rules_v4_out, rules_v6_in and rules_v6_out.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateFirewallRuleSet,
+				},
+			},
+			"rule_set_conflicts": {
+				Type:        schema.TypeList,
+				Description: "Conflicts which were resolved while merging the rule sets. Rules which match the same traffic as a previous rule are skipped.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"status": {
 				Type:        schema.TypeString,
 				Description: "Status indicates the status of the object",
@@ -188,32 +226,52 @@ func resourceGridscaleFirewallRead(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("%s error setting network: %v", errorPrefix, err)
 	}
 
+	//Remove default rules and rules merged from rule sets, we don't want to display them
+	ruleSets, err := fwu.DecodeRuleSets(convSOStrings(d.Get("rule_sets").([]interface{})))
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	explicitRules := getExplicitFirewallRules(d)
+	rules := fwu.RemoveRuleSetRules(
+		gsclient.FirewallRules{
+			RulesV4In:  fwu.RemoveDefaultFirewallInboundRules(props.Rules.RulesV4In),
+			RulesV4Out: props.Rules.RulesV4Out,
+			RulesV6In:  fwu.RemoveDefaultFirewallInboundRules(props.Rules.RulesV6In),
+			RulesV6Out: props.Rules.RulesV6Out,
+		},
+		explicitRules,
+		ruleSets,
+	)
+
 	//Get rules_v4_in
-	rulesV4InWODefaultRules := fwu.RemoveDefaultFirewallInboundRules(props.Rules.RulesV4In)
-	rulesV4In := convFirewallRuleSliceToInterfaceSlice(rulesV4InWODefaultRules)
+	rulesV4In := convFirewallRuleSliceToInterfaceSlice(rules.RulesV4In)
 	if err = d.Set("rules_v4_in", rulesV4In); err != nil {
 		return fmt.Errorf("%s error setting rules_v4_in: %v", errorPrefix, err)
 	}
 
 	//Get rules_v4_out
-	rulesV4Out := convFirewallRuleSliceToInterfaceSlice(props.Rules.RulesV4Out)
+	rulesV4Out := convFirewallRuleSliceToInterfaceSlice(rules.RulesV4Out)
 	if err = d.Set("rules_v4_out", rulesV4Out); err != nil {
 		return fmt.Errorf("%s error setting rules_v4_out: %v", errorPrefix, err)
 	}
 
 	//Get rules_v6_in
-	rulesV6InWODefaultRules := fwu.RemoveDefaultFirewallInboundRules(props.Rules.RulesV6In)
-	rulesV6In := convFirewallRuleSliceToInterfaceSlice(rulesV6InWODefaultRules)
+	rulesV6In := convFirewallRuleSliceToInterfaceSlice(rules.RulesV6In)
 	if err = d.Set("rules_v6_in", rulesV6In); err != nil {
 		return fmt.Errorf("%s error setting rules_v6_in: %v", errorPrefix, err)
 	}
 
 	//Get rules_v6_out
-	rulesV6Out := convFirewallRuleSliceToInterfaceSlice(props.Rules.RulesV6Out)
+	rulesV6Out := convFirewallRuleSliceToInterfaceSlice(rules.RulesV6Out)
 	if err = d.Set("rules_v6_out", rulesV6Out); err != nil {
 		return fmt.Errorf("%s error setting rules_v6_out: %v", errorPrefix, err)
 	}
 
+	_, conflicts := fwu.MergeRuleSets(explicitRules, ruleSets)
+	if err = d.Set("rule_set_conflicts", conflicts); err != nil {
+		return fmt.Errorf("%s error setting rule_set_conflicts: %v", errorPrefix, err)
+	}
+
 	if err = d.Set("labels", props.Labels); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
@@ -223,32 +281,23 @@ func resourceGridscaleFirewallRead(d *schema.ResourceData, meta interface{}) err
 
 func resourceGridscaleFirewallCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*gsclient.Client)
-	var rulesV4In, rulesV4Out, rulesV6In, rulesV6Out []gsclient.FirewallRuleProperties
-	//Get firewall rules from schema
-	if attr, ok := d.GetOk("rules_v4_in"); ok {
-		rulesV4In = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v4_out"); ok {
-		rulesV4Out = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v6_in"); ok {
-		rulesV6In = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v6_out"); ok {
-		rulesV6Out = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
+	//Get firewall rules from schema, merged with the rule sets
+	rules, _, err := mergeFirewallRuleSets(d)
+	if err != nil {
+		return err
 	}
 	//at least one rules in firewall create request
-	if len(rulesV4In) == 0 && len(rulesV4Out) == 0 && len(rulesV6In) == 0 && len(rulesV6Out) == 0 {
+	if len(rules.RulesV4In) == 0 && len(rules.RulesV4Out) == 0 && len(rules.RulesV6In) == 0 && len(rules.RulesV6Out) == 0 {
 		return errors.New("at least 1 firewall rule in create request")
 	}
 	requestBody := gsclient.FirewallCreateRequest{
 		Name:   d.Get("name").(string),
 		Labels: convSOStrings(d.Get("labels").(*schema.Set).List()),
 		Rules: gsclient.FirewallRules{
-			RulesV6In:  fwu.AddDefaultFirewallInboundRules(rulesV6In, true),
-			RulesV6Out: rulesV6Out,
-			RulesV4In:  fwu.AddDefaultFirewallInboundRules(rulesV4In, false),
-			RulesV4Out: rulesV4Out,
+			RulesV6In:  fwu.AddDefaultFirewallInboundRules(rules.RulesV6In, true),
+			RulesV6Out: rules.RulesV6Out,
+			RulesV4In:  fwu.AddDefaultFirewallInboundRules(rules.RulesV4In, false),
+			RulesV4Out: rules.RulesV4Out,
 		},
 	}
 
@@ -270,21 +319,12 @@ func resourceGridscaleFirewallUpdate(d *schema.ResourceData, meta interface{}) e
 	client := meta.(*gsclient.Client)
 	errorPrefix := fmt.Sprintf("update firewall (%s) resource -", d.Id())
 
-	var rulesV4In, rulesV4Out, rulesV6In, rulesV6Out []gsclient.FirewallRuleProperties
-	if attr, ok := d.GetOk("rules_v4_in"); ok {
-		rulesV4In = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v4_out"); ok {
-		rulesV4Out = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v6_in"); ok {
-		rulesV6In = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
-	}
-	if attr, ok := d.GetOk("rules_v6_out"); ok {
-		rulesV6Out = convInterfaceSliceToFirewallRulesSlice(attr.([]interface{}))
+	rules, _, err := mergeFirewallRuleSets(d)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
 	//at least one rules in firewall create request
-	if len(rulesV4In) == 0 && len(rulesV4Out) == 0 && len(rulesV6In) == 0 && len(rulesV6Out) == 0 {
+	if len(rules.RulesV4In) == 0 && len(rules.RulesV4Out) == 0 && len(rules.RulesV6In) == 0 && len(rules.RulesV6Out) == 0 {
 		return fmt.Errorf("%s error: At least 1 firewall rule in update request", errorPrefix)
 	}
 	labels := convSOStrings(d.Get("labels").(*schema.Set).List())
@@ -293,15 +333,15 @@ func resourceGridscaleFirewallUpdate(d *schema.ResourceData, meta interface{}) e
 		Labels: &labels,
 	}
 	requestBody.Rules = &gsclient.FirewallRules{
-		RulesV6In:  fwu.AddDefaultFirewallInboundRules(rulesV6In, true),
-		RulesV6Out: rulesV6Out,
-		RulesV4In:  fwu.AddDefaultFirewallInboundRules(rulesV4In, false),
-		RulesV4Out: rulesV4Out,
+		RulesV6In:  fwu.AddDefaultFirewallInboundRules(rules.RulesV6In, true),
+		RulesV6Out: rules.RulesV6Out,
+		RulesV4In:  fwu.AddDefaultFirewallInboundRules(rules.RulesV4In, false),
+		RulesV4Out: rules.RulesV4Out,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
-	err := client.UpdateFirewall(ctx, d.Id(), requestBody)
+	err = client.UpdateFirewall(ctx, d.Id(), requestBody)
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
@@ -325,6 +365,47 @@ func resourceGridscaleFirewallDelete(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
+// resourceGetter is implemented by *schema.ResourceData and *schema.ResourceDiff
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// getExplicitFirewallRules reads the rules declared in rules_v4_in, rules_v4_out,
+// rules_v6_in and rules_v6_out
+func getExplicitFirewallRules(d resourceGetter) gsclient.FirewallRules {
+	return gsclient.FirewallRules{
+		RulesV4In:  convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v4_in").([]interface{})),
+		RulesV4Out: convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v4_out").([]interface{})),
+		RulesV6In:  convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v6_in").([]interface{})),
+		RulesV6Out: convInterfaceSliceToFirewallRulesSlice(d.Get("rules_v6_out").([]interface{})),
+	}
+}
+
+// firewallRuleSetsKnown reports whether all rule sets of a list attribute are
+// known at plan time (e.g. not computed from a data source which is read later)
+func firewallRuleSetsKnown(d *schema.ResourceDiff, key string) bool {
+	if !d.NewValueKnown(key) {
+		return false
+	}
+	for i := range d.Get(key).([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("%s.%d", key, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFirewallRuleSets merges the rule sets of `rule_sets` into the explicitly
+// declared rules, it returns the merged rules and the resolved conflicts
+func mergeFirewallRuleSets(d resourceGetter) (gsclient.FirewallRules, []string, error) {
+	ruleSets, err := fwu.DecodeRuleSets(convSOStrings(d.Get("rule_sets").([]interface{})))
+	if err != nil {
+		return gsclient.FirewallRules{}, nil, err
+	}
+	rules, conflicts := fwu.MergeRuleSets(getExplicitFirewallRules(d), ruleSets)
+	return rules, conflicts, nil
+}
+
 // convFirewallRuleSliceToInterfaceSlice converts slice of firewall rules to slice of interface
 func convFirewallRuleSliceToInterfaceSlice(rules []gsclient.FirewallRuleProperties) []interface{} {
 	res := make([]interface{}, 0)
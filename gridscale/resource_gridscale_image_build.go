@@ -0,0 +1,309 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+)
+
+func resourceGridscaleImageBuild() *schema.Resource {
+	return &schema.Resource{
+		Read:          resourceGridscaleImageBuildRead,
+		Create:        resourceGridscaleImageBuildCreate,
+		Update:        resourceGridscaleImageBuildUpdate,
+		Delete:        resourceGridscaleImageBuildDelete,
+		CustomizeDiff: customizeImageBuildDiff,
+		Schema: map[string]*schema.Schema{
+			"server_uuid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "UUID of the server the template is built from. The template is built from its boot storage.",
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"server_uuid", "storage_uuid"},
+			},
+			"storage_uuid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "UUID of the storage the template is built from. If the storage is attached to a server, the server is shut down during the build.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Name of the template. If `version` is set, it is appended to the name.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Version of the image. Changing it builds a new template.",
+			},
+			"labels": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "List of labels of the template.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"restart_server": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Start the server again after the build, if it was running before.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of the template. It can not be set, as templates are created without a description.",
+			},
+			"template_uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the built template.",
+			},
+			"template_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the built template.",
+			},
+			"location_uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The location the template is placed.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the template.",
+			},
+			"capacity": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The capacity of the template in GB.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date and time the template was created.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// imageBuildTemplateName returns the name of the template of an image build
+func imageBuildTemplateName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, version)
+}
+
+// serverBootStorage returns the UUID of the storage a server boots from. A server with a single
+// storage boots from it, even if it is not marked as boot device.
+func serverBootStorage(storages []gsclient.ServerStorageRelationProperties) (string, bool) {
+	for _, storage := range storages {
+		if storage.BootDevice {
+			return storage.ObjectUUID, true
+		}
+	}
+	if len(storages) == 1 {
+		return storages[0].ObjectUUID, true
+	}
+	return "", false
+}
+
+// customizeImageBuildDiff shows the new name of the template, when `name` changes
+func customizeImageBuildDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("version") {
+		return nil
+	}
+	templateName := imageBuildTemplateName(d.Get("name").(string), d.Get("version").(string))
+	if d.Get("template_name").(string) != templateName {
+		return d.SetNew("template_name", templateName)
+	}
+	return nil
+}
+
+func resourceGridscaleImageBuildRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("read image build (%s) resource -", d.Id())
+	template, err := client.GetTemplate(context.Background(), d.Id())
+	if err != nil {
+		if requestError, ok := err.(gsclient.RequestError); ok {
+			if requestError.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	props := template.Properties
+	if err = d.Set("template_uuid", props.ObjectUUID); err != nil {
+		return fmt.Errorf("%s error setting template_uuid: %v", errorPrefix, err)
+	}
+	if err = d.Set("template_name", props.Name); err != nil {
+		return fmt.Errorf("%s error setting template_name: %v", errorPrefix, err)
+	}
+	if err = d.Set("labels", props.Labels); err != nil {
+		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
+	}
+	if err = d.Set("description", props.Description); err != nil {
+		return fmt.Errorf("%s error setting description: %v", errorPrefix, err)
+	}
+	if err = d.Set("location_uuid", props.LocationUUID); err != nil {
+		return fmt.Errorf("%s error setting location_uuid: %v", errorPrefix, err)
+	}
+	if err = d.Set("status", props.Status); err != nil {
+		return fmt.Errorf("%s error setting status: %v", errorPrefix, err)
+	}
+	if err = d.Set("capacity", props.Capacity); err != nil {
+		return fmt.Errorf("%s error setting capacity: %v", errorPrefix, err)
+	}
+	if err = d.Set("create_time", props.CreateTime.String()); err != nil {
+		return fmt.Errorf("%s error setting create_time: %v", errorPrefix, err)
+	}
+	return nil
+}
+
+// imageBuildSource returns the server (if any) and the storage an image is built from
+func imageBuildSource(ctx context.Context, client *gsclient.Client, d *schema.ResourceData) (serverUUID, storageUUID string, err error) {
+	if v, ok := d.GetOk("server_uuid"); ok {
+		serverUUID = v.(string)
+		server, err := client.GetServer(ctx, serverUUID)
+		if err != nil {
+			return "", "", fmt.Errorf("error getting server %s: %v", serverUUID, err)
+		}
+		storageUUID, ok = serverBootStorage(server.Properties.Relations.Storages)
+		if !ok {
+			return "", "", fmt.Errorf("server %s has no boot storage", serverUUID)
+		}
+		return serverUUID, storageUUID, nil
+	}
+	storageUUID = d.Get("storage_uuid").(string)
+	storage, err := client.GetStorage(ctx, storageUUID)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting storage %s: %v", storageUUID, err)
+	}
+	// a storage is attached to one server at most
+	if servers := storage.Properties.Relations.Servers; len(servers) > 0 {
+		serverUUID = servers[0].ObjectUUID
+	}
+	return serverUUID, storageUUID, nil
+}
+
+func resourceGridscaleImageBuildCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	serverUUID, storageUUID, err := imageBuildSource(ctx, client, d)
+	if err != nil {
+		return fmt.Errorf("create image build resource - %v", err)
+	}
+	errorPrefix := fmt.Sprintf("create image build of storage (%s) resource -", storageUUID)
+	if err = d.Set("storage_uuid", storageUUID); err != nil {
+		return fmt.Errorf("%s error setting storage_uuid: %v", errorPrefix, err)
+	}
+
+	templateName := imageBuildTemplateName(d.Get("name").(string), d.Get("version").(string))
+	var snapshotUUID string
+	// takeSnapshot snapshots the storage, it has to run while the server is off
+	takeSnapshot := func(ctx context.Context) error {
+		snapshot, err := client.CreateStorageSnapshot(ctx, storageUUID, gsclient.StorageSnapshotCreateRequest{
+			Name: fmt.Sprintf("%s-build", templateName),
+		})
+		if err != nil {
+			return err
+		}
+		snapshotUUID = snapshot.ObjectUUID
+		log.Printf("[DEBUG] Snapshot (%v) of storage (%v) is taken for template %v", snapshotUUID, storageUUID, templateName)
+		return nil
+	}
+	switch {
+	case serverUUID == "":
+		err = takeSnapshot(ctx)
+	case d.Get("restart_server").(bool):
+		// the server is started again after the snapshot, if it was running
+		err = globalServerStatusList.runActionRequireServerOff(ctx, client, serverUUID, true, takeSnapshot)
+	default:
+		if err = globalServerStatusList.shutdownServerSynchronously(ctx, client, serverUUID); err == nil {
+			err = takeSnapshot(ctx)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s error taking snapshot: %v", errorPrefix, err)
+	}
+
+	template, err := client.CreateTemplate(ctx, gsclient.TemplateCreateRequest{
+		Name:         templateName,
+		SnapshotUUID: snapshotUUID,
+		Labels:       convSOStrings(d.Get("labels").(*schema.Set).List()),
+	})
+	if err == nil {
+		d.SetId(template.ObjectUUID)
+		log.Printf("The id for the new template has been set to %v", template.ObjectUUID)
+	}
+	// the template does not depend on the snapshot, so the snapshot is removed in any case. The
+	// template is built already, so a failed removal does not fail the build.
+	errDeleteSnapshot := errHandler.SuppressHTTPErrorCodes(
+		client.DeleteStorageSnapshot(ctx, storageUUID, snapshotUUID),
+		http.StatusNotFound,
+	)
+	if errDeleteSnapshot != nil {
+		log.Printf("[WARN] %s error deleting snapshot %s, it has to be deleted manually: %v", errorPrefix, snapshotUUID, errDeleteSnapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("%s error creating template: %v", errorPrefix, err)
+	}
+	return resourceGridscaleImageBuildRead(d, meta)
+}
+
+func resourceGridscaleImageBuildUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("update image build (%s) resource -", d.Id())
+
+	labels := convSOStrings(d.Get("labels").(*schema.Set).List())
+	requestBody := gsclient.TemplateUpdateRequest{
+		Name:   imageBuildTemplateName(d.Get("name").(string), d.Get("version").(string)),
+		Labels: &labels,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	err := client.UpdateTemplate(ctx, d.Id(), requestBody)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return resourceGridscaleImageBuildRead(d, meta)
+}
+
+func resourceGridscaleImageBuildDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("delete image build (%s) resource -", d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	err := errHandler.SuppressHTTPErrorCodes(
+		client.DeleteTemplate(ctx, d.Id()),
+		http.StatusNotFound,
+	)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceGridscaleImageBuildBasic(t *testing.T) {
+	var object gsclient.Template
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleTemplateDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleImageBuildConfigBasic(name, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleTemplateExists("gridscale_image_build.foo", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_image_build.foo", "template_name", fmt.Sprintf("%s-1.0.0", name)),
+					resource.TestCheckResourceAttrPair(
+						"gridscale_image_build.foo", "storage_uuid", "gridscale_storage.foo", "id"),
+					resource.TestCheckResourceAttrPair(
+						"gridscale_image_build.foo", "template_uuid", "gridscale_image_build.foo", "id"),
+				),
+			},
+			{
+				Config: testAccCheckResourceGridscaleImageBuildConfigBasic(name, "newname"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleTemplateExists("gridscale_image_build.foo", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_image_build.foo", "template_name", "newname-1.0.0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceGridscaleImageBuildConfigBasic(name, templateName string) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "foo" {
+  name   = "%s"
+  capacity = 10
+  template {
+    template_uuid = "4db64bfc-9fb2-4976-80b5-94ff43b1233a"
+    hostname = "ubuntu"
+  }
+}
+
+resource "gridscale_server" "foo" {
+  name   = "%s"
+  cores = 1
+  memory = 1
+  power = true
+  storage {
+    object_uuid = gridscale_storage.foo.id
+  }
+}
+
+resource "gridscale_image_build" "foo" {
+  name = "%s"
+  version = "1.0.0"
+  server_uuid = gridscale_server.foo.id
+  labels = ["golden-image"]
+}
+`, name, name, templateName)
+}
+
+func TestImageBuildTemplateName(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Version  string
+		Expected string
+	}
+	testCases := []testCase{
+		{"web", "", "web"},
+		{"web", "1.2.0", "web-1.2.0"},
+		{"web", "20261019", "web-20261019"},
+	}
+	for _, test := range testCases {
+		if name := imageBuildTemplateName(test.Name, test.Version); name != test.Expected {
+			t.Errorf("name %q, version %q: expected %q, got %q", test.Name, test.Version, test.Expected, name)
+		}
+	}
+}
+
+func TestServerBootStorage(t *testing.T) {
+	type testCase struct {
+		Storages []gsclient.ServerStorageRelationProperties
+		Expected string
+		Found    bool
+	}
+	testCases := []testCase{
+		{nil, "", false},
+		{[]gsclient.ServerStorageRelationProperties{{ObjectUUID: "a"}}, "a", true},
+		{[]gsclient.ServerStorageRelationProperties{{ObjectUUID: "a"}, {ObjectUUID: "b", BootDevice: true}}, "b", true},
+		{[]gsclient.ServerStorageRelationProperties{{ObjectUUID: "a"}, {ObjectUUID: "b"}}, "", false},
+	}
+	for i, test := range testCases {
+		storageUUID, found := serverBootStorage(test.Storages)
+		if storageUUID != test.Expected || found != test.Found {
+			t.Errorf("case %d: expected (%q, %v), got (%q, %v)", i, test.Expected, test.Found, storageUUID, found)
+		}
+	}
+}
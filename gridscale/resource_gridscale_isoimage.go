@@ -12,12 +12,16 @@ import (
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
 )
 
+// isoImageSourceFile is uploaded to Object Storage and pulled by the API from a presigned URL
+var isoImageSourceFile = sourceFileTarget{attribute: "source_url", presign: true, forceNew: true}
+
 func resourceGridscaleISOImage() *schema.Resource {
-	return &schema.Resource{
-		Read:   resourceGridscaleISOImageRead,
-		Create: resourceGridscaleISOImageCreate,
-		Update: resourceGridscaleISOImageUpdate,
-		Delete: resourceGridscaleISOImageDelete,
+	r := &schema.Resource{
+		Read:          resourceGridscaleISOImageRead,
+		Create:        resourceGridscaleISOImageCreate,
+		Update:        resourceGridscaleISOImageUpdate,
+		Delete:        resourceGridscaleISOImageDelete,
+		CustomizeDiff: isoImageSourceFile.customizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -31,7 +35,8 @@ func resourceGridscaleISOImage() *schema.Resource {
 			"source_url": {
 				Type:        schema.TypeString,
 				Description: "Contains the source URL of the ISO image that it was originally fetched from.",
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 			},
 			"server": {
@@ -137,6 +142,8 @@ func resourceGridscaleISOImage() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 	}
+	isoImageSourceFile.addSchema(r.Schema)
+	return r
 }
 
 func resourceGridscaleISOImageRead(d *schema.ResourceData, meta interface{}) error {
@@ -231,6 +238,13 @@ func resourceGridscaleISOImageCreate(d *schema.ResourceData, meta interface{}) e
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
 	defer cancel()
+	if _, ok := d.GetOk("source_file"); ok {
+		sourceURL, err := isoImageSourceFile.upload(ctx, d)
+		if err != nil {
+			return fmt.Errorf("create ISO-Image resource - %v", err)
+		}
+		requestBody.SourceURL = sourceURL
+	}
 	response, err := client.CreateISOImage(ctx, requestBody)
 	if err != nil {
 		return err
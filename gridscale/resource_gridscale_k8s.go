@@ -2,20 +2,28 @@ package gridscale
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+	kcu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/kubeconfig-utils"
+	paasu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/paas-utils"
 
 	"log"
 )
@@ -25,8 +33,19 @@ const (
 	k8sLabelPrefix                 = "#gsk#"
 	k8sRocketStorageSupportRelease = "1.26"
 	k8sMultiNodePoolSupportRelease = "1.30"
+	k8sReadinessPollInterval       = 10 * time.Second
 )
 
+// k8sLogDeliveryPreflightCheck checks the bucket of the control plane log delivery
+var k8sLogDeliveryPreflightCheck = s3PreflightCheck{
+	description: "log delivery",
+	enabled:     "log_delivery_preflight_check",
+	endpoint:    "log_delivery_endpoint",
+	bucket:      "log_delivery_bucket",
+	accessKey:   "log_delivery_access_key",
+	secretKey:   "log_delivery_secret_key",
+}
+
 // ResourceGridscaleK8sModeler struct represents a modeler of the gridscale k8s resource.
 type ResourceGridscaleK8sModeler struct{}
 
@@ -36,20 +55,60 @@ type ResourceGridscaleK8sValidator struct{}
 func resourceGridscaleK8s() *schema.Resource {
 	var resourceModeler ResourceGridscaleK8sModeler
 	return &schema.Resource{
-		Create: resourceGridscaleK8sCreate,
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := resourceGridscaleK8sCreate(d, meta); err != nil {
+				return diag.FromErr(err)
+			}
+			diags := append(syncK8sKubeconfigFile(d), waitForK8sClusterReady(ctx, d)...)
+			return append(diags, k8sLogDeliveryPreflightCheck.verify(ctx, d)...)
+		},
 		Read:   resourceGridscaleK8sRead,
 		Delete: resourceGridscaleK8sDelete,
-		Update: resourceGridscaleK8sUpdate,
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if err := resourceGridscaleK8sUpdate(d, meta); err != nil {
+				return diag.FromErr(err)
+			}
+			diags := append(syncK8sKubeconfigFile(d), waitForK8sClusterReady(ctx, d)...)
+			return append(diags, k8sLogDeliveryPreflightCheck.verify(ctx, d)...)
+		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
-			template, err := deriveK8sTemplateFromResourceDiff(meta.(*gsclient.Client), d)
+			// Rotating the credentials changes the kubeconfig, so that dependent providers get the new one
+			if d.Id() != "" && d.HasChange("rotate_credentials_trigger") {
+				for _, key := range []string{"kubeconfig", "kubeconfig_expiration_time", "client_certificate", "client_key", "token"} {
+					if err := d.SetNewComputed(key); err != nil {
+						return err
+					}
+				}
+			}
+			// The kubeconfig may have been renewed on refresh, the kubeconfig file is rewritten by an update then
+			if d.Id() != "" && k8sKubeconfigFileOutdated(d) {
+				if err := d.SetNewComputed("kubeconfig_file_sha256"); err != nil {
+					return err
+				}
+			}
+			client := meta.(*gsclient.Client)
+			template, err := deriveK8sTemplateFromResourceDiff(client, d)
 
 			if err != nil {
 				return err
 			}
-			return validateK8sParameters(d, *template)
+			// Check the upgrade path, if the release or GSK version of an existing cluster changes
+			if d.Id() != "" && (d.HasChange("release") || d.HasChange("gsk_version")) {
+				currentTemplate, err := deriveK8sTemplateFromUUID(client, d.Get("service_template_uuid").(string))
+				if err != nil {
+					return err
+				}
+				if err = checkK8sUpgradePath(*currentTemplate, *template); err != nil {
+					return err
+				}
+			}
+			if err = validateK8sParameters(d, *template); err != nil {
+				return err
+			}
+			return k8sLogDeliveryPreflightCheck.validateDiff(ctx, d)
 		},
 		Schema: resourceModeler.buildInputSchema(),
 		Timeouts: &schema.ResourceTimeout{
@@ -118,7 +177,7 @@ func (rgk8sm *ResourceGridscaleK8sModeler) buildInputSchema() map[string]*schema
 			Description: "Rocket storage per worker node (in GiB).",
 		},
 	}
-	return map[string]*schema.Schema{
+	inputSchema := map[string]*schema.Schema{
 		"name": {
 			Type:         schema.TypeString,
 			Description:  "The human-readable name of the object. It supports the full UTF-8 character set, with a maximum of 64 characters.",
@@ -131,6 +190,79 @@ func (rgk8sm *ResourceGridscaleK8sModeler) buildInputSchema() map[string]*schema
 			Computed:    true,
 			Sensitive:   true,
 		},
+		"credential_renewal_threshold": k8sCredentialRenewalThresholdSchema(),
+		"rotate_credentials_trigger": {
+			Type:        schema.TypeString,
+			Description: "An arbitrary value, the kubeconfig is renewed whenever it changes.",
+			Optional:    true,
+		},
+		"kubeconfig_file": {
+			Type:        schema.TypeList,
+			Description: "Write the kubeconfig of the cluster to a local file, optionally merged into an existing kubeconfig.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"path": {
+						Type:         schema.TypeString,
+						Description:  "The path of the kubeconfig file, e.g. \"~/.kube/config\".",
+						Required:     true,
+						ValidateFunc: validation.NoZeroValues,
+					},
+					"context_name": {
+						Type:        schema.TypeString,
+						Description: "The name of the context, cluster and user of the cluster in the kubeconfig file. Defaults to the name of the cluster.",
+						Optional:    true,
+					},
+					"merge": {
+						Type:        schema.TypeBool,
+						Description: "Merge the cluster into an existing kubeconfig file instead of overwriting it.",
+						Optional:    true,
+						Default:     true,
+					},
+					"file_permission": {
+						Type:         schema.TypeString,
+						Description:  "The permission of the kubeconfig file in octal notation, e.g. \"0600\".",
+						Optional:     true,
+						Default:      "0600",
+						ValidateFunc: validateFilePermission,
+					},
+				},
+			},
+		},
+		"kubeconfig_file_sha256": {
+			Type:        schema.TypeString,
+			Description: "SHA256 checksum of the kubeconfig which was written to the kubeconfig file.",
+			Computed:    true,
+		},
+		"wait_for_ready": {
+			Type:        schema.TypeList,
+			Description: "Wait until the cluster is ready after it has been created or updated, using its kubeconfig.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"timeout": {
+						Type:         schema.TypeString,
+						Description:  "How long to wait for the cluster to become ready, e.g. \"15m\".",
+						Optional:     true,
+						Default:      "10m",
+						ValidateFunc: validateDuration,
+					},
+					"check_nodes": {
+						Type:        schema.TypeBool,
+						Description: "Wait until the expected number of nodes of the node pools are `Ready`.",
+						Optional:    true,
+						Default:     false,
+					},
+					"node_pool_label": {
+						Type:        schema.TypeString,
+						Description: "The node label which contains the name of the node pool of a node. If set, the ready nodes are checked per node pool, otherwise the total number of ready nodes is checked.",
+						Optional:    true,
+					},
+				},
+			},
+		},
 		"listen_port": {
 			Type:        schema.TypeSet,
 			Description: "The port number where this k8s service accepts connections.",
@@ -349,6 +481,12 @@ func (rgk8sm *ResourceGridscaleK8sModeler) buildInputSchema() map[string]*schema
 			Computed:    true,
 			Optional:    true,
 		},
+		"log_delivery_preflight_check": {
+			Type:        schema.TypeBool,
+			Description: "Check at plan time and after apply that the log delivery bucket exists and the keys can write to it.",
+			Optional:    true,
+			Default:     false,
+		},
 		"k8s_hubble": {
 			Type:        schema.TypeBool,
 			Description: "Enables Hubble Integration.",
@@ -356,6 +494,10 @@ func (rgk8sm *ResourceGridscaleK8sModeler) buildInputSchema() map[string]*schema
 			Optional:    true,
 		},
 	}
+	for key, credentialSchema := range k8sCredentialsSchema() {
+		inputSchema[key] = credentialSchema
+	}
+	return inputSchema
 }
 
 /*
@@ -606,27 +748,19 @@ func resourceGridscaleK8sRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
+	// if expiration_time of kubeconfig is reached or within the threshold, renew it and get new kubeconfig
+	threshold, _ := time.ParseDuration(d.Get("credential_renewal_threshold").(string))
+	paas, err = renewExpiredK8sCredentials(client, paas, threshold)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
 	props := paas.Properties
-	creds := props.Credentials
 	if err = d.Set("name", props.Name); err != nil {
 		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
 	}
-	if len(creds) > 0 {
-		// if expiration_time of kubeconfig is reached, renew it and get new kubeconfig
-		if creds[0].ExpirationTime.Before(time.Now()) {
-			err = client.RenewK8sCredentials(context.Background(), d.Id())
-			if err != nil {
-				return fmt.Errorf("%s error renewing k8s kubeconfig: %v", errorPrefix, err)
-			}
-			paas, err = client.GetPaaSService(context.Background(), d.Id())
-			if err != nil {
-				return fmt.Errorf("%s error: %v", errorPrefix, err)
-			}
-			props = paas.Properties
-			creds = props.Credentials
-		}
-		if err = d.Set("kubeconfig", creds[0].KubeConfig); err != nil {
-			return fmt.Errorf("%s error setting kubeconfig: %v", errorPrefix, err)
+	if len(props.Credentials) > 0 {
+		if err = setK8sCredentials(d, props.Credentials[0]); err != nil {
+			return fmt.Errorf("%s %v", errorPrefix, err)
 		}
 	}
 	template, err := deriveK8sTemplateFromUUID(client, props.ServiceTemplateUUID)
@@ -820,40 +954,25 @@ func resourceGridscaleK8sRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("%s error setting listen ports: %v", errorPrefix, err)
 	}
 
-	// Iterate over node pools fetched from source to collect them
-	nodePoolsSetInterface, isNodePoolsSet := props.Parameters["pools"]
+	// Collect the node pools fetched from source. Only the pools declared in this resource are
+	// collected, pools managed by gridscale_k8s_node_pool resources are skipped. If no pool is
+	// declared yet (e.g. on import), all pools are collected, except the pools marked as managed
+	// by gridscale_k8s_node_pool resources.
+	declaredNodePools := make(map[string]bool)
+	for _, nodePool := range d.Get("node_pool").([]interface{}) {
+		if nodePoolMap, ok := nodePool.(map[string]interface{}); ok {
+			declaredNodePools[nodePoolMap["name"].(string)] = true
+		}
+	}
+	adoptableNodePools := managedK8sNodePools(k8sNodePools(paas), props.Labels)
 	nodePools := make([]map[string]interface{}, 0)
-
-	if isNodePoolsSet {
-		nodePoolsSet := nodePoolsSetInterface.([]interface{})
-
-		for _, nodePoolSetInterface := range nodePoolsSet {
-			nodePoolRead := make(map[string]interface{}, 0)
-			nodePoolSet := nodePoolSetInterface.(map[string]interface{})
-
-			if name, isNameSet := nodePoolSet["name"]; isNameSet {
-				nodePoolRead["name"] = name
-			}
-			if nodeCount, isNodeCountSet := nodePoolSet["count"]; isNodeCountSet {
-				nodePoolRead["node_count"] = nodeCount
-			}
-			if memory, isMemorySet := nodePoolSet["ram"]; isMemorySet {
-				nodePoolRead["memory"] = memory
-			}
-			if cores, isCoresSet := nodePoolSet["cores"]; isCoresSet {
-				nodePoolRead["cores"] = cores
-			}
-			if storage, isStorageSet := nodePoolSet["storage"]; isStorageSet {
-				nodePoolRead["storage"] = storage
-			}
-			if storageType, isStorageTypeSet := nodePoolSet["storage_type"]; isStorageTypeSet {
-				nodePoolRead["storage_type"] = storageType
-			}
-			if rocketStorage, isRocketStorageSet := nodePoolSet["rocket_storage"]; isRocketStorageSet {
-				nodePoolRead["rocket_storage"] = rocketStorage
-			}
-			nodePools = append(nodePools, nodePoolRead)
+	for _, nodePoolSetInterface := range k8sNodePools(paas) {
+		nodePoolRead := flattenK8sNodePool(nodePoolSetInterface.(map[string]interface{}))
+		name, _ := nodePoolRead["name"].(string)
+		if len(declaredNodePools) > 0 && !declaredNodePools[name] || len(declaredNodePools) == 0 && !adoptableNodePools[name] {
+			continue
 		}
+		nodePools = append(nodePools, nodePoolRead)
 	}
 	// Set node pools
 	if err = d.Set("node_pool", nodePools); err != nil {
@@ -877,8 +996,8 @@ func resourceGridscaleK8sRead(d *schema.ResourceData, meta interface{}) error {
 			return fmt.Errorf("%s error setting cluster_traffic_encryption: %v", errorPrefix, err)
 		}
 	}
-	//Set labels
-	if err = d.Set("labels", props.Labels); err != nil {
+	//Set labels, the labels marking node pools are not shown
+	if err = d.Set("labels", removeK8sNodePoolLabels(props.Labels)); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
 	//Get all available networks
@@ -942,17 +1061,10 @@ func resourceGridscaleK8sCreate(d *schema.ResourceData, meta interface{}) error
 		nodePoolsRequested := nodePoolsRequestedInterface.([]interface{})
 		nodePools := make([]map[string]interface{}, 0)
 
-		for index, _ := range nodePoolsRequested {
-			nodePool := make(map[string]interface{}, 0)
-			nodePool["name"] = d.Get(fmt.Sprintf("node_pool.%d.name", index))
-			nodePool["count"] = d.Get(fmt.Sprintf("node_pool.%d.node_count", index))
-			nodePool["ram"] = d.Get(fmt.Sprintf("node_pool.%d.memory", index))
-			nodePool["cores"] = d.Get(fmt.Sprintf("node_pool.%d.cores", index))
-			nodePool["storage"] = d.Get(fmt.Sprintf("node_pool.%d.storage", index))
-			nodePool["storage_type"] = d.Get(fmt.Sprintf("node_pool.%d.storage_type", index))
-			nodePool["rocket_storage"] = d.Get(fmt.Sprintf("node_pool.%d.rocket_storage", index))
-
-			nodePools = append(nodePools, nodePool)
+		for index := range nodePoolsRequested {
+			nodePools = append(nodePools, expandK8sNodePool(func(key string) interface{} {
+				return d.Get(fmt.Sprintf("node_pool.%d.%s", index, key))
+			}))
 		}
 		parameters["pools"] = nodePools
 	}
@@ -1082,6 +1194,18 @@ func resourceGridscaleK8sUpdate(d *schema.ResourceData, meta interface{}) error
 	client := meta.(*gsclient.Client)
 	errorPrefix := fmt.Sprintf("update k8s (%s) resource -", d.Id())
 
+	if d.HasChange("rotate_credentials_trigger") {
+		ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+		if err := client.RenewK8sCredentials(ctx, d.Id()); err != nil {
+			return fmt.Errorf("%s error renewing k8s kubeconfig: %v", errorPrefix, err)
+		}
+	}
+	// The cluster does not need to be updated if only settings of the provider changed
+	if !d.HasChangesExcept("rotate_credentials_trigger", "credential_renewal_threshold", "wait_for_ready", "kubeconfig_file", "kubeconfig_file_sha256", "log_delivery_preflight_check") {
+		return resourceGridscaleK8sRead(d, meta)
+	}
+
 	labels := convSOStrings(d.Get("labels").(*schema.Set).List())
 	requestBody := gsclient.PaaSServiceUpdateRequest{
 		Name:   d.Get("name").(string),
@@ -1103,19 +1227,24 @@ func resourceGridscaleK8sUpdate(d *schema.ResourceData, meta interface{}) error
 		nodePoolsRequested := nodePoolsRequestedInterface.([]interface{})
 		nodePools := make([]map[string]interface{}, 0)
 
-		for index, _ := range nodePoolsRequested {
-			nodePool := make(map[string]interface{}, 0)
-			nodePool["name"] = d.Get(fmt.Sprintf("node_pool.%d.name", index))
-			nodePool["count"] = d.Get(fmt.Sprintf("node_pool.%d.node_count", index))
-			nodePool["ram"] = d.Get(fmt.Sprintf("node_pool.%d.memory", index))
-			nodePool["cores"] = d.Get(fmt.Sprintf("node_pool.%d.cores", index))
-			nodePool["storage"] = d.Get(fmt.Sprintf("node_pool.%d.storage", index))
-			nodePool["storage_type"] = d.Get(fmt.Sprintf("node_pool.%d.storage_type", index))
-			nodePool["rocket_storage"] = d.Get(fmt.Sprintf("node_pool.%d.rocket_storage", index))
-
-			nodePools = append(nodePools, nodePool)
+		for index := range nodePoolsRequested {
+			nodePools = append(nodePools, expandK8sNodePool(func(key string) interface{} {
+				return d.Get(fmt.Sprintf("node_pool.%d.%s", index, key))
+			}))
 		}
-		parameters["pools"] = nodePools
+		// The pools parameter is shared with gridscale_k8s_node_pool resources, so the node pools are
+		// matched by name and the pools which are not declared in this resource are kept.
+		unlock := globalK8sClusterLockList.lock(d.Id())
+		defer unlock()
+		paas, err := client.GetPaaSService(context.Background(), d.Id())
+		if err != nil {
+			return fmt.Errorf("%s error: %v", errorPrefix, err)
+		}
+		oldNodePools, _ := d.GetChange("node_pool")
+		managedNodePools := managedK8sNodePools(oldNodePools.([]interface{}), paas.Properties.Labels)
+		parameters["pools"] = mergeK8sNodePools(k8sNodePools(paas), nodePools, managedNodePools)
+		// keep the labels marking the node pools of gridscale_k8s_node_pool resources
+		labels = append(labels, k8sNodePoolLabels(paas.Properties.Labels)...)
 	}
 
 	// Set cluster CIDR if it is set
@@ -1249,158 +1378,115 @@ func resourceGridscaleK8sDelete(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
+	// the cluster is deleted already, so a kubeconfig file which can not be cleaned up does not fail the deletion
+	if file := expandK8sKubeconfigFile(d.Get("kubeconfig_file").([]interface{}), d.Get("name").(string)); file != nil {
+		if err = file.remove(); err != nil {
+			log.Printf("[WARN] %s error removing the cluster from kubeconfig file %s: %v", errorPrefix, file.path, err)
+		}
+	}
+	return nil
+}
+
+// checkK8sUpgradePath checks if a k8s cluster can be switched from the current template to the target template.
+// Downgrades and upgrades skipping a minor release are rejected.
+func checkK8sUpgradePath(current, target gsclient.PaaSTemplate) error {
+	if current.Properties.ObjectUUID == target.Properties.ObjectUUID {
+		return nil
+	}
+	currentRelease, err := NewRelease(current.Properties.Release)
+	if err != nil {
+		return fmt.Errorf("invalid release %q of the current template: %v", current.Properties.Release, err)
+	}
+	targetRelease, err := NewRelease(target.Properties.Release)
+	if err != nil {
+		return fmt.Errorf("invalid release %q of the requested template: %v", target.Properties.Release, err)
+	}
+	if err = currentRelease.CheckIfK8SUpgradeIsAllowed(targetRelease); err != nil {
+		return err
+	}
+	if !currentRelease.Equal(&targetRelease.Version) {
+		return nil
+	}
+	currentVersion, err := NewRelease(current.Properties.Version)
+	if err != nil {
+		return nil
+	}
+	targetVersion, err := NewRelease(target.Properties.Version)
+	if err != nil {
+		return nil
+	}
+	if targetVersion.LessThan(&currentVersion.Version) {
+		return fmt.Errorf("downgrading a Kubernetes cluster from GSK version %s to %s is not supported", current.Properties.Version, target.Properties.Version)
+	}
 	return nil
 }
 
+// k8sParameterAttributes maps the attributes of the k8s resource to the parameters of the service
+var k8sParameterAttributes = map[string]string{
+	"cluster_cidr":               "k8s_cluster_cidr",
+	"cluster_traffic_encryption": "k8s_cluster_traffic_encryption",
+	"oidc_enabled":               "k8s_oidc_enabled",
+	"oidc_issuer_url":            "k8s_oidc_issuer_url",
+	"oidc_client_id":             "k8s_oidc_client_id",
+	"oidc_username_claim":        "k8s_oidc_username_claim",
+	"oidc_groups_claim":          "k8s_oidc_groups_claim",
+	"oidc_signing_algs":          "k8s_oidc_signing_algs",
+	"oidc_groups_prefix":         "k8s_oidc_groups_prefix",
+	"oidc_username_prefix":       "k8s_oidc_username_prefix",
+	"oidc_required_claim":        "k8s_oidc_required_claim",
+	"oidc_ca_pem":                "k8s_oidc_ca_pem",
+	"kube_apiserver_log_enabled": "k8s_kube_apiserver_log_enabled",
+	"audit_log_enabled":          "k8s_audit_log_enabled",
+	"audit_log_level":            "k8s_audit_log_level",
+	"log_delivery":               "k8s_log_delivery",
+	"log_delivery_bucket":        "k8s_log_delivery_bucket",
+	"log_delivery_access_key":    "k8s_log_delivery_access_key",
+	"log_delivery_secret_key":    "k8s_log_delivery_secret_key",
+	"log_delivery_interval":      "k8s_log_delivery_interval",
+	"log_delivery_endpoint":      "k8s_log_delivery_endpoint",
+	"k8s_hubble":                 "k8s_hubble",
+}
+
+// k8sNodePoolParameterAttributes maps the attributes of a node pool to the fields of the `pools` parameter
+var k8sNodePoolParameterAttributes = map[string]string{
+	"node_count":     "count",
+	"memory":         "ram",
+	"cores":          "cores",
+	"storage":        "storage",
+	"storage_type":   "storage_type",
+	"rocket_storage": "rocket_storage",
+}
+
 func validateK8sParameters(d *schema.ResourceDiff, template gsclient.PaaSTemplate) error {
-	var errorMessages []string
 	validator := &ResourceGridscaleK8sValidator{}
 	err := validator.checkIfTemplateSupportsMultiNodePools(template)
 
 	if err != nil {
 		return err
 	}
-	templateParameterNodePools, templateParameterNodePoolsFound := template.Properties.ParametersSchema["pools"]
+	v := newPaaSParameterValidation(d, k8sParameterAttributes)
 	nodePoolsRequestedInterface, isNodePoolsRequested := d.GetOk("node_pool")
-
-	if templateParameterNodePoolsFound && isNodePoolsRequested {
-		nodePoolsRequested := nodePoolsRequestedInterface.([]interface{})
-		for index, _ := range nodePoolsRequested {
-			nodePoolParameterMemory, mem_ok := templateParameterNodePools.Schema.Schema["ram"]
-			if memory, ok := d.GetOk(fmt.Sprintf("node_pool.%d.memory", index)); ok && mem_ok {
-				if memory.(int) < nodePoolParameterMemory.Min || memory.(int) > nodePoolParameterMemory.Max {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.memory' value. Value must stay between %d and %d\n",
-							index,
-							nodePoolParameterMemory.Min,
-							nodePoolParameterMemory.Max,
-						),
-					)
-				}
-			}
-
-			nodePoolParameterCores, core_ok := templateParameterNodePools.Schema.Schema["cores"]
-			if core, ok := d.GetOk(fmt.Sprintf("node_pool.%d.cores", index)); ok && core_ok {
-				if core.(int) < nodePoolParameterCores.Min || core.(int) > nodePoolParameterCores.Max {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.cores' value. Value must stay between %d and %d\n",
-							index,
-							nodePoolParameterCores.Min,
-							nodePoolParameterCores.Max,
-						),
-					)
-				}
-			}
-
-			nodePoolParameterCount, worker_count_ok := templateParameterNodePools.Schema.Schema["count"]
-			if node_count, ok := d.GetOk(fmt.Sprintf("node_pool.%d.node_count", index)); ok && worker_count_ok {
-				if node_count.(int) < nodePoolParameterCount.Min || node_count.(int) > nodePoolParameterCount.Max {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.node_count' value. Value must stay between %d and %d\n",
-							index,
-							nodePoolParameterCount.Min,
-							nodePoolParameterCount.Max,
-						),
-					)
-				}
-			}
-
-			nodePoolParameterStorage, storage_ok := templateParameterNodePools.Schema.Schema["storage"]
-			if storage, ok := d.GetOk(fmt.Sprintf("node_pool.%d.storage", index)); ok && storage_ok {
-				if storage.(int) < nodePoolParameterStorage.Min || storage.(int) > nodePoolParameterStorage.Max {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.storage' value. Value must stay between %d and %d\n",
-							index,
-							nodePoolParameterStorage.Min,
-							nodePoolParameterStorage.Max,
-						),
-					)
-				}
-			}
-
-			nodePoolParameterRocketStorage, rocket_storage_ok := templateParameterNodePools.Schema.Schema["rocket_storage"]
-			if rocket_storage, ok := d.GetOk(fmt.Sprintf("node_pool.%d.rocket_storage", index)); ok && rocket_storage_ok {
-				rocketStorageValidation := true
-				featureReleaseCompabilityValidation := true
-				supportedRelease, err := NewRelease(k8sRocketStorageSupportRelease)
-				if err != nil {
-					panic("Something went wrong at backend side parsing of version string expected for support of rocket storage at k8s.")
-				}
-				requestedRelease, err := NewRelease(template.Properties.Release)
-				if err != nil {
-					errorMessages = append(errorMessages, "The release doesn't match a valid version string.")
-					featureReleaseCompabilityValidation = false
-				}
-				if featureReleaseCompabilityValidation {
-					err := requestedRelease.CheckIfFeatureIsKnown(&Feature{Description: "rocket storage", Release: *supportedRelease})
-					if err != nil {
-						errorMessages = append(errorMessages, err.Error())
-						rocketStorageValidation = false
-					}
-				}
-				if rocketStorageValidation && (rocket_storage.(int) < nodePoolParameterRocketStorage.Min || rocket_storage.(int) > nodePoolParameterRocketStorage.Max) {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.rocket_storage' value. Value must stay between %d and %d\n",
-							index,
-							nodePoolParameterRocketStorage.Min,
-							nodePoolParameterRocketStorage.Max,
-						),
-					)
-				}
-			}
-
-			nodePoolParameterStorageType, storage_type_ok := templateParameterNodePools.Schema.Schema["storage_type"]
-			if storage_type, ok := d.GetOk(fmt.Sprintf("node_pool.%d.storage_type", index)); ok && storage_type_ok {
-				var isValid bool
-				for _, allowedValue := range nodePoolParameterStorageType.Allowed {
-					if storage_type.(string) == allowedValue {
-						isValid = true
-					}
-				}
-				if !isValid {
-					errorMessages = append(
-						errorMessages,
-						fmt.Sprintf(
-							"Invalid 'node_pool.%d.storage_type' value. Value must be one of these:\n\t%s",
-							index,
-							strings.Join(nodePoolParameterStorageType.Allowed, "\n\t"),
-						),
-					)
-				}
+	if isNodePoolsRequested {
+		for index := range nodePoolsRequestedInterface.([]interface{}) {
+			attrPrefix := fmt.Sprintf("node_pool.%d.", index)
+			for _, errorMessage := range validateK8sNodePoolParameters(template, attrPrefix, func(key string) (interface{}, bool) {
+				return d.GetOk(attrPrefix + key)
+			}) {
+				v.addError(errorMessage)
 			}
 		}
 	}
 
-	templateParameterClusterCIDR, templateParameterFound := template.Properties.ParametersSchema["k8s_cluster_cidr"]
+	_, templateParameterFound := template.Properties.ParametersSchema["k8s_cluster_cidr"]
 	if clusterCIDR, ok := d.GetOk("cluster_cidr"); ok {
 		// if the template doesn't support cluster_cidr, return error if it is set
 		if !templateParameterFound {
-			errorMessages = append(errorMessages, "The template doesn't support cluster_cidr. Please remove it from your configuration.\n")
-		} else {
+			v.addError("The template doesn't support cluster_cidr. Please remove it from your configuration.\n")
+		} else if clusterCIDR.(string) != "" {
 			// if the template supports cluster_cidr, validate the value
-			if clusterCIDR.(string) != "" {
-				_, _, err := net.ParseCIDR(clusterCIDR.(string))
-				if err != nil {
-					errorMessages = append(errorMessages, "Invalid value for PaaS template release. Value must be a valid CIDR.\n")
-				}
-			}
-			// if cluster_cidr_template is immutable, return error if it is set during k8s creation
-			// and it is changed during k8s update
-			if templateParameterClusterCIDR.Immutable {
-				oldClusterCIDR, _ := d.GetChange("cluster_cidr")
-				if oldClusterCIDR != "" && d.HasChange("cluster_cidr") {
-					errorMessages = append(errorMessages, "Cannot change parameter cluster_cidr, because it is immutable.\n")
-				}
+			_, _, err := net.ParseCIDR(clusterCIDR.(string))
+			if err != nil {
+				v.addError("Invalid value for PaaS template release. Value must be a valid CIDR.\n")
 			}
 		}
 	}
@@ -1409,26 +1495,9 @@ func validateK8sParameters(d *schema.ResourceDiff, template gsclient.PaaSTemplat
 		if _, ok := template.Properties.ParametersSchema["k8s_oidc_issuer_url"]; ok {
 			validMode := regexp.MustCompile(`^https:\/\/.*`)
 			if !validMode.MatchString(interfaceOIDCIssuerURL.(string)) {
-				errorMessages = append(errorMessages, fmt.Sprintf("Invalid OIDC 'issuer_url' value. Example value: '%s'\n", "https://example.io"))
-			}
-		}
-	}
-
-	templateParameterOIDCSigningAlgs, templateParameterFound := template.Properties.ParametersSchema["k8s_oidc_signing_algs"]
-	if oidcSigningAlgs, ok := d.GetOk("oidc_signing_algs"); ok && templateParameterFound {
-		var isValid bool
-		for _, allowedValue := range templateParameterOIDCSigningAlgs.Allowed {
-			if oidcSigningAlgs.(string) == allowedValue {
-				isValid = true
+				v.addError(fmt.Sprintf("Invalid OIDC 'issuer_url' value. Example value: '%s'\n", "https://example.io"))
 			}
 		}
-		if !isValid {
-			errorMessages = append(errorMessages,
-				fmt.Sprintf("Invalid OIDC 'signing_algs' value. Value must be one of these:\n\t%s",
-					strings.Join(templateParameterOIDCSigningAlgs.Allowed, "\n\t"),
-				),
-			)
-		}
 	}
 
 	if interfaceOIDCCAPEM, ok := d.GetOk("oidc_ca_pem"); ok {
@@ -1440,76 +1509,299 @@ func validateK8sParameters(d *schema.ResourceDiff, template gsclient.PaaSTemplat
 		}
 	}
 
-	templateParameterAuditLogLevel, templateParameterFound := template.Properties.ParametersSchema["k8s_audit_log_level"]
-	if auditLogLevel, ok := d.GetOk("audit_log_level"); ok && templateParameterFound {
-		var isValid bool
-		for _, allowedValue := range templateParameterAuditLogLevel.Allowed {
-			if auditLogLevel.(string) == allowedValue {
-				isValid = true
+	if interfaceLogDeliveryEndpoint, ok := d.GetOk("log_delivery_endpoint"); ok {
+		if _, ok := template.Properties.ParametersSchema["k8s_log_delivery_endpoint"]; ok {
+			validMode := regexp.MustCompile(`^https:\/\/.*`)
+			if !validMode.MatchString(interfaceLogDeliveryEndpoint.(string)) {
+				v.addError(fmt.Sprintf("Invalid 'log_delivery_endpoint' value. Example value: '%s'\n", "https://gos3.io"))
 			}
 		}
-		if !isValid {
-			errorMessages = append(errorMessages,
-				fmt.Sprintf("Invalid 'audit_log_level' value. Value must be one of these:\n\t%s",
-					strings.Join(templateParameterAuditLogLevel.Allowed, "\n\t"),
-				),
-			)
+	}
+	return v.validate(template, paasu.Options{})
+}
+
+// validateK8sNodePoolParameters validates the attributes of a node pool against the `pools` parameter schema
+// of the template. getOk returns the value of an attribute of the node pool, attrPrefix is prepended to the
+// attribute names in the error messages.
+func validateK8sNodePoolParameters(template gsclient.PaaSTemplate, attrPrefix string, getOk func(key string) (interface{}, bool)) []string {
+	var errorMessages []string
+	templateParameterNodePools, templateParameterNodePoolsFound := template.Properties.ParametersSchema["pools"]
+	if !templateParameterNodePoolsFound {
+		return nil
+	}
+	pool := make(map[string]interface{})
+	attributePaths := make(map[string]string)
+	for attribute, parameter := range k8sNodePoolParameterAttributes {
+		attributePaths[parameter] = attrPrefix + attribute
+		if value, ok := getOk(attribute); ok {
+			pool[parameter] = value
 		}
 	}
 
-	if interfaceLogDeliveryBucket, ok := d.GetOk("log_delivery_bucket"); ok {
-		if templateParameterLogDeliveryBucket, ok := template.Properties.ParametersSchema["k8s_log_delivery_bucket"]; ok {
-			validMode := regexp.MustCompile(templateParameterLogDeliveryBucket.Regex)
-			if !validMode.MatchString(interfaceLogDeliveryBucket.(string)) {
-				errorMessages = append(errorMessages, fmt.Sprintf("Invalid 'log_delivery_bucket' value. Example value: '%s'\n", "foo"))
-			}
+	if _, ok := pool["rocket_storage"]; ok {
+		supportedRelease, err := NewRelease(k8sRocketStorageSupportRelease)
+		if err != nil {
+			panic("Something went wrong at backend side parsing of version string expected for support of rocket storage at k8s.")
+		}
+		requestedRelease, err := NewRelease(template.Properties.Release)
+		if err != nil {
+			errorMessages = append(errorMessages, "The release doesn't match a valid version string.")
+			delete(pool, "rocket_storage")
+		} else if err := requestedRelease.CheckIfFeatureIsKnown(&Feature{Description: "rocket storage", Release: *supportedRelease}); err != nil {
+			errorMessages = append(errorMessages, err.Error())
+			delete(pool, "rocket_storage")
 		}
 	}
+	return append(errorMessages, paasu.ValidateParameters(templateParameterNodePools.Schema.Schema, pool, paasu.Options{
+		AttributePaths: attributePaths,
+	})...)
+}
 
-	if interfaceLogDeliveryAccessKey, ok := d.GetOk("log_delivery_access_key"); ok {
-		if templateParameterLogDeliveryAccessKey, ok := template.Properties.ParametersSchema["k8s_log_delivery_access_key"]; ok {
-			validMode := regexp.MustCompile(templateParameterLogDeliveryAccessKey.Regex)
-			if !validMode.MatchString(interfaceLogDeliveryAccessKey.(string)) {
-				errorMessages = append(errorMessages, fmt.Sprintf("Invalid 'log_delivery_access_key' value. Example value: '%s'\n", "011000010110001101100011011001010111001101110011"))
-			}
+// waitForK8sClusterReady waits until the API server of the cluster reports ready and, if requested, the
+// expected number of nodes are ready. As the cluster exists already, a failed wait is reported as a warning,
+// so that the cluster is not tainted.
+func waitForK8sClusterReady(ctx context.Context, d *schema.ResourceData) diag.Diagnostics {
+	if _, ok := d.GetOk("wait_for_ready.0"); !ok {
+		return nil
+	}
+	timeout, _ := time.ParseDuration(d.Get("wait_for_ready.0.timeout").(string))
+	checkNodes := d.Get("wait_for_ready.0.check_nodes").(bool)
+	poolLabel := d.Get("wait_for_ready.0.node_pool_label").(string)
+
+	// the expected number of ready nodes per node pool
+	expectedNodes := make(map[string]int)
+	for _, nodePool := range d.Get("node_pool").([]interface{}) {
+		pool := nodePool.(map[string]interface{})
+		if poolLabel == "" {
+			expectedNodes[""] += pool["node_count"].(int)
+		} else {
+			expectedNodes[pool["name"].(string)] = pool["node_count"].(int)
 		}
 	}
 
-	if interfaceLogDeliverySecretKey, ok := d.GetOk("log_delivery_secret_key"); ok {
-		if templateParameterLogDeliverySecretKey, ok := template.Properties.ParametersSchema["k8s_log_delivery_secret_key"]; ok {
-			validMode := regexp.MustCompile(templateParameterLogDeliverySecretKey.Regex)
-			if !validMode.MatchString(interfaceLogDeliverySecretKey.(string)) {
-				errorMessages = append(errorMessages, fmt.Sprintf("Invalid 'log_delivery_secret_key' value. Example value: '%s'\n", "011100110110010101100011011100100110010101110100"))
+	err := func() error {
+		creds, err := kcu.ParseCredentials(d.Get("kubeconfig").(string))
+		if err != nil {
+			return err
+		}
+		client, err := kcu.NewHTTPClient(creds, 30*time.Second)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		for {
+			err = kcu.CheckAPIServerReady(ctx, client, creds)
+			if err == nil && checkNodes {
+				err = checkK8sReadyNodes(ctx, client, creds, poolLabel, expectedNodes)
+			}
+			if err == nil {
+				return nil
+			}
+			log.Printf("[DEBUG] k8s cluster (%s) is not ready yet: %v", d.Id(), err)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timeout after %s, last error: %v", timeout, err)
+			case <-time.After(k8sReadinessPollInterval):
 			}
 		}
+	}()
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Kubernetes cluster is not ready",
+			Detail: fmt.Sprintf("The k8s cluster (%s) was applied, but did not become ready: %v. "+
+				"Resources depending on the cluster may fail until it is ready.", d.Id(), err),
+		}}
 	}
+	return nil
+}
 
-	if interfaceLogDeliveryInterval, ok := d.GetOk("log_delivery_interval"); ok {
-		if templateParameterLogDeliveryInterval, ok := template.Properties.ParametersSchema["k8s_log_delivery_interval"]; ok {
-			if interfaceLogDeliveryInterval.(int) < templateParameterLogDeliveryInterval.Min || interfaceLogDeliveryInterval.(int) > templateParameterLogDeliveryInterval.Max {
-				errorMessages = append(
-					errorMessages,
-					fmt.Sprintf(
-						"Invalid 'log_delivery_interval' value. Value must stay between %d and %d\n",
-						templateParameterLogDeliveryInterval.Min,
-						templateParameterLogDeliveryInterval.Max,
-					),
-				)
+// checkK8sReadyNodes checks if at least the expected number of nodes are ready per node pool
+func checkK8sReadyNodes(ctx context.Context, client *http.Client, creds kcu.Credentials, poolLabel string, expectedNodes map[string]int) error {
+	readyNodes, err := kcu.CountReadyNodes(ctx, client, creds, poolLabel)
+	if err != nil {
+		return err
+	}
+	var notReady []string
+	for pool, expected := range expectedNodes {
+		if readyNodes[pool] < expected {
+			if pool == "" {
+				notReady = append(notReady, fmt.Sprintf("%d of %d nodes ready", readyNodes[pool], expected))
+			} else {
+				notReady = append(notReady, fmt.Sprintf("node pool %s: %d of %d nodes ready", pool, readyNodes[pool], expected))
 			}
 		}
 	}
+	if len(notReady) != 0 {
+		sort.Strings(notReady)
+		return errors.New(strings.Join(notReady, ", "))
+	}
+	return nil
+}
 
-	if interfaceLogDeliveryEndpoint, ok := d.GetOk("log_delivery_endpoint"); ok {
-		if _, ok := template.Properties.ParametersSchema["k8s_log_delivery_endpoint"]; ok {
-			validMode := regexp.MustCompile(`^https:\/\/.*`)
-			if !validMode.MatchString(interfaceLogDeliveryEndpoint.(string)) {
-				errorMessages = append(errorMessages, fmt.Sprintf("Invalid 'log_delivery_endpoint' value. Example value: '%s'\n", "https://gos3.io"))
+// k8sKubeconfigFileMutex serializes the access to kubeconfig files, several clusters may be merged into the same file
+var k8sKubeconfigFileMutex sync.Mutex
+
+// k8sKubeconfigFile is a local kubeconfig file, the kubeconfig of a cluster is written to
+type k8sKubeconfigFile struct {
+	path        string
+	contextName string
+	merge       bool
+	permission  os.FileMode
+}
+
+// expandK8sKubeconfigFile converts the `kubeconfig_file` block. It returns nil, if the block is not set.
+func expandK8sKubeconfigFile(blocks []interface{}, clusterName string) *k8sKubeconfigFile {
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	block := blocks[0].(map[string]interface{})
+	file := &k8sKubeconfigFile{
+		path:        block["path"].(string),
+		contextName: block["context_name"].(string),
+		merge:       block["merge"].(bool),
+		permission:  0600,
+	}
+	if file.contextName == "" {
+		file.contextName = clusterName
+	}
+	if permission, err := strconv.ParseUint(block["file_permission"].(string), 8, 32); err == nil {
+		file.permission = os.FileMode(permission)
+	}
+	if strings.HasPrefix(file.path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			file.path = filepath.Join(home, file.path[2:])
+		}
+	}
+	return file
+}
+
+// write writes the kubeconfig to the file. If merge is enabled, the cluster is merged into the existing file.
+func (f *k8sKubeconfigFile) write(kubeconfig string) error {
+	k8sKubeconfigFileMutex.Lock()
+	defer k8sKubeconfigFileMutex.Unlock()
+	var existing []byte
+	if f.merge {
+		var err error
+		existing, err = os.ReadFile(f.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	content, err := kcu.MergeConfig(existing, kubeconfig, f.contextName)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(f.path, content, f.permission)
+}
+
+// remove removes the cluster from the file. If merge is disabled, the whole file is removed.
+func (f *k8sKubeconfigFile) remove() error {
+	k8sKubeconfigFileMutex.Lock()
+	defer k8sKubeconfigFileMutex.Unlock()
+	if !f.merge {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	existing, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content, err := kcu.RemoveConfig(existing, f.contextName)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(f.path, content, f.permission)
+}
+
+// syncK8sKubeconfigFile writes the kubeconfig file after the cluster has been created or updated. If the file
+// or context name changed, the cluster is removed from the previous file first. As the cluster exists already,
+// errors are reported as warnings, so that the cluster is not tainted.
+func syncK8sKubeconfigFile(d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	warn := func(err error) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Kubeconfig file is not up to date",
+			Detail:   fmt.Sprintf("The kubeconfig of k8s cluster (%s) could not be written: %v", d.Id(), err),
+		})
+	}
+	if d.HasChange("kubeconfig_file") || d.HasChange("name") {
+		oldBlocks, _ := d.GetChange("kubeconfig_file")
+		oldName, _ := d.GetChange("name")
+		if file := expandK8sKubeconfigFile(oldBlocks.([]interface{}), oldName.(string)); file != nil && !d.IsNewResource() {
+			current := expandK8sKubeconfigFile(d.Get("kubeconfig_file").([]interface{}), d.Get("name").(string))
+			if current == nil || current.path != file.path || current.contextName != file.contextName {
+				if err := file.remove(); err != nil {
+					warn(err)
+				}
 			}
 		}
 	}
+	// the checksum is only recorded if the file has been written, so that a failed write is retried by the next apply
+	var checksum string
+	if file := expandK8sKubeconfigFile(d.Get("kubeconfig_file").([]interface{}), d.Get("name").(string)); file != nil {
+		if err := file.write(d.Get("kubeconfig").(string)); err != nil {
+			warn(err)
+		} else {
+			checksum = k8sKubeconfigSHA256(d.Get("kubeconfig").(string))
+		}
+	}
+	if err := d.Set("kubeconfig_file_sha256", checksum); err != nil {
+		warn(err)
+	}
+	return diags
+}
+
+// k8sKubeconfigSHA256 returns the checksum of a kubeconfig
+func k8sKubeconfigSHA256(kubeconfig string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(kubeconfig)))
+}
 
-	if len(errorMessages) != 0 {
-		return errors.New(strings.Join(errorMessages, ""))
+// k8sKubeconfigFileOutdated checks whether the kubeconfig file does not contain the current kubeconfig,
+// e.g. because the credentials were renewed on refresh
+func k8sKubeconfigFileOutdated(d *schema.ResourceDiff) bool {
+	blocks := d.Get("kubeconfig_file").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return false
 	}
-	return nil
+	return d.Get("kubeconfig_file_sha256").(string) != k8sKubeconfigSHA256(d.Get("kubeconfig").(string))
+}
+
+// writeFileAtomically writes a file via a temporary file, so that the file is never written partially
+func writeFileAtomically(path string, content []byte, permission os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), permission); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// validateFilePermission validates a file permission in octal notation, e.g. "0600"
+func validateFilePermission(v interface{}, k string) (ws []string, errors []error) {
+	permission, err := strconv.ParseUint(v.(string), 8, 32)
+	if err != nil || permission > 0777 {
+		errors = append(errors, fmt.Errorf("%s is not a valid file permission in octal notation, e.g. \"0600\"", k))
+	}
+	return
 }
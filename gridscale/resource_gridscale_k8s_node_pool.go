@@ -0,0 +1,443 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// k8sClusterLockList serializes the read-modify-write operations on the `pools`
+// parameter of k8s clusters, as it is shared by the gridscale_k8s resource and
+// all gridscale_k8s_node_pool resources of a cluster.
+type k8sClusterLockList struct {
+	list map[string]*sync.Mutex
+	mux  sync.Mutex
+}
+
+// lock locks the cluster and returns the function to unlock it
+func (l *k8sClusterLockList) lock(id string) func() {
+	l.mux.Lock()
+	clusterMux, ok := l.list[id]
+	if !ok {
+		clusterMux = &sync.Mutex{}
+		l.list[id] = clusterMux
+	}
+	l.mux.Unlock()
+
+	clusterMux.Lock()
+	log.Printf("[DEBUG] LOCK ACQUIRED to update node pools of k8s cluster (%v)", id)
+	return func() {
+		clusterMux.Unlock()
+		log.Printf("[DEBUG] LOCK RELEASED! Node pools of k8s cluster (%v) are updated", id)
+	}
+}
+
+// globalK8sClusterLockList global list of the locks of all k8s clusters in terraform
+var globalK8sClusterLockList = k8sClusterLockList{
+	list: make(map[string]*sync.Mutex),
+}
+
+// k8sNodePoolLabelPrefix is the prefix of the reserved cluster labels, which mark the node pools
+// managed by gridscale_k8s_node_pool resources (e.g. "#tf#gridscale_k8s_node_pool#gpu")
+const k8sNodePoolLabelPrefix = "#tf#gridscale_k8s_node_pool#"
+
+func resourceGridscaleK8sNodePool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridscaleK8sNodePoolCreate,
+		Read:   resourceGridscaleK8sNodePoolRead,
+		Update: resourceGridscaleK8sNodePoolUpdate,
+		Delete: resourceGridscaleK8sNodePoolDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridscaleK8sNodePoolImport,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			// The cluster is not created yet, the node pool is validated on apply
+			if !d.NewValueKnown("cluster_id") {
+				return nil
+			}
+			client := meta.(*gsclient.Client)
+			paas, err := client.GetPaaSService(ctx, d.Get("cluster_id").(string))
+			if err != nil {
+				return fmt.Errorf("error getting k8s cluster: %v", err)
+			}
+			if d.Id() == "" {
+				if _, found := findK8sNodePool(k8sNodePools(paas), d.Get("name").(string)); found {
+					return fmt.Errorf("node pool %q already exists in k8s cluster %s, please import it", d.Get("name").(string), paas.Properties.ObjectUUID)
+				}
+			}
+			template, err := deriveK8sTemplateFromUUID(client, paas.Properties.ServiceTemplateUUID)
+			if err != nil {
+				return err
+			}
+			validator := &ResourceGridscaleK8sValidator{}
+			if err = validator.checkIfTemplateSupportsMultiNodePools(*template); err != nil {
+				return err
+			}
+			errorMessages := validateK8sNodePoolParameters(*template, "", d.GetOk)
+			if len(errorMessages) != 0 {
+				return fmt.Errorf("%s", strings.Join(errorMessages, ""))
+			}
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "ID of the k8s cluster.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of node pool. It has to be unique within the k8s cluster.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"node_count": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Number of worker nodes.",
+			},
+			"cores": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Cores per worker node.",
+			},
+			"memory": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Memory per worker node (in GiB).",
+			},
+			"storage": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Storage per worker node (in GiB).",
+			},
+			"storage_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Storage type.",
+			},
+			"rocket_storage": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Rocket storage per worker node (in GiB).",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Minute),
+			Update: schema.DefaultTimeout(45 * time.Minute),
+			Delete: schema.DefaultTimeout(45 * time.Minute),
+		},
+	}
+}
+
+func resourceGridscaleK8sNodePoolRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("read k8s node pool (%s) resource -", d.Id())
+	clusterID, name, err := parseK8sNodePoolID(d.Id())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	paas, err := client.GetPaaSService(context.Background(), clusterID)
+	if err != nil {
+		if requestError, ok := err.(gsclient.RequestError); ok {
+			if requestError.StatusCode == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	index, found := findK8sNodePool(k8sNodePools(paas), name)
+	if !found {
+		log.Printf("[DEBUG] node pool %s is not found in k8s cluster %s", name, clusterID)
+		d.SetId("")
+		return nil
+	}
+	if err = d.Set("cluster_id", clusterID); err != nil {
+		return fmt.Errorf("%s error setting cluster_id: %v", errorPrefix, err)
+	}
+	pool := k8sNodePools(paas)[index].(map[string]interface{})
+	for key, value := range flattenK8sNodePool(pool) {
+		if err = d.Set(key, value); err != nil {
+			return fmt.Errorf("%s error setting %s: %v", errorPrefix, key, err)
+		}
+	}
+	return nil
+}
+
+// resourceGridscaleK8sNodePoolImport marks the imported node pool as managed by this resource, so
+// that the gridscale_k8s resource does not take it over
+func resourceGridscaleK8sNodePoolImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	clusterID, name, err := parseK8sNodePoolID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	err = updateK8sNodePools(context.Background(), meta.(*gsclient.Client), clusterID, name, true, func(pools []interface{}) ([]interface{}, error) {
+		if _, found := findK8sNodePool(pools, name); !found {
+			return nil, fmt.Errorf("node pool %q does not exist in k8s cluster %s", name, clusterID)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import k8s node pool (%s) resource - error: %v", d.Id(), err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGridscaleK8sNodePoolCreate(d *schema.ResourceData, meta interface{}) error {
+	clusterID := d.Get("cluster_id").(string)
+	name := d.Get("name").(string)
+	errorPrefix := fmt.Sprintf("create k8s node pool (%s) resource -", name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	err := updateK8sNodePools(ctx, meta.(*gsclient.Client), clusterID, name, true, func(pools []interface{}) ([]interface{}, error) {
+		if _, found := findK8sNodePool(pools, name); found {
+			return nil, fmt.Errorf("node pool %q already exists in k8s cluster %s, please import it", name, clusterID)
+		}
+		return append(pools, expandK8sNodePool(d.Get)), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", clusterID, name))
+	log.Printf("The id for k8s node pool %s has been set to %v", name, d.Id())
+	return resourceGridscaleK8sNodePoolRead(d, meta)
+}
+
+func resourceGridscaleK8sNodePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+	clusterID := d.Get("cluster_id").(string)
+	name := d.Get("name").(string)
+	errorPrefix := fmt.Sprintf("update k8s node pool (%s) resource -", d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	err := updateK8sNodePools(ctx, meta.(*gsclient.Client), clusterID, name, true, func(pools []interface{}) ([]interface{}, error) {
+		index, found := findK8sNodePool(pools, name)
+		if !found {
+			return nil, fmt.Errorf("node pool %q does not exist in k8s cluster %s", name, clusterID)
+		}
+		pools[index] = expandK8sNodePool(d.Get)
+		return pools, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return resourceGridscaleK8sNodePoolRead(d, meta)
+}
+
+func resourceGridscaleK8sNodePoolDelete(d *schema.ResourceData, meta interface{}) error {
+	clusterID := d.Get("cluster_id").(string)
+	name := d.Get("name").(string)
+	errorPrefix := fmt.Sprintf("delete k8s node pool (%s) resource -", d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	err := updateK8sNodePools(ctx, meta.(*gsclient.Client), clusterID, name, false, func(pools []interface{}) ([]interface{}, error) {
+		index, found := findK8sNodePool(pools, name)
+		if !found {
+			return nil, nil
+		}
+		return append(pools[:index], pools[index+1:]...), nil
+	})
+	if err != nil {
+		if requestError, ok := err.(gsclient.RequestError); ok && requestError.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	return nil
+}
+
+// updateK8sNodePools reads the `pools` parameter of a k8s cluster, modifies it and writes it back,
+// while the cluster is locked. The node pool with the given name is marked as managed by a
+// gridscale_k8s_node_pool resource, if managed is true, otherwise the mark is removed. If modify
+// returns nil pools, only the mark is updated.
+func updateK8sNodePools(ctx context.Context, client *gsclient.Client, clusterID, name string, managed bool, modify func(pools []interface{}) ([]interface{}, error)) error {
+	unlock := globalK8sClusterLockList.lock(clusterID)
+	defer unlock()
+
+	paas, err := client.GetPaaSService(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	pools, err := modify(k8sNodePools(paas))
+	if err != nil {
+		return err
+	}
+	labels, labelsChanged := setK8sNodePoolLabel(paas.Properties.Labels, name, managed)
+	if pools == nil && !labelsChanged {
+		return nil
+	}
+	requestBody := gsclient.PaaSServiceUpdateRequest{
+		Labels: &labels,
+	}
+	if pools != nil {
+		requestBody.Parameters = map[string]interface{}{
+			"pools": pools,
+		}
+	}
+	return client.UpdatePaaSService(ctx, clusterID, requestBody)
+}
+
+// setK8sNodePoolLabel adds (managed is true) or removes the label marking a node pool as managed by
+// a gridscale_k8s_node_pool resource. It reports whether the labels have changed.
+func setK8sNodePoolLabel(labels []string, name string, managed bool) ([]string, bool) {
+	label := k8sNodePoolLabelPrefix + name
+	result := make([]string, 0, len(labels)+1)
+	for _, l := range labels {
+		if l != label {
+			result = append(result, l)
+		}
+	}
+	if managed {
+		result = append(result, label)
+	}
+	return result, !hasAllLabels(labels, result) || !hasAllLabels(result, labels)
+}
+
+// k8sNodePoolLabels returns the labels of a k8s cluster, which mark the node pools managed by
+// gridscale_k8s_node_pool resources
+func k8sNodePoolLabels(labels []string) []string {
+	result := make([]string, 0)
+	for _, label := range labels {
+		if strings.HasPrefix(label, k8sNodePoolLabelPrefix) {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// removeK8sNodePoolLabels returns the labels of a k8s cluster without the labels marking node pools
+func removeK8sNodePoolLabels(labels []string) []string {
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if !strings.HasPrefix(label, k8sNodePoolLabelPrefix) {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// managedK8sNodePools returns the names of the node pools managed by a gridscale_k8s resource: the
+// pools in its state, except the pools marked as managed by gridscale_k8s_node_pool resources
+func managedK8sNodePools(statePools []interface{}, labels []string) map[string]bool {
+	managed := make(map[string]bool)
+	for _, nodePool := range statePools {
+		if nodePoolMap, ok := nodePool.(map[string]interface{}); ok {
+			managed[nodePoolMap["name"].(string)] = true
+		}
+	}
+	for _, label := range k8sNodePoolLabels(labels) {
+		delete(managed, strings.TrimPrefix(label, k8sNodePoolLabelPrefix))
+	}
+	return managed
+}
+
+// parseK8sNodePoolID splits the ID of a node pool into the ID of the cluster and the name of the pool
+func parseK8sNodePoolID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid node pool ID %q, expected format: <cluster_id>/<node_pool_name>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// k8sNodePools returns a copy of the `pools` parameter of a k8s cluster
+func k8sNodePools(paas gsclient.PaaSService) []interface{} {
+	pools, _ := paas.Properties.Parameters["pools"].([]interface{})
+	return append(make([]interface{}, 0, len(pools)), pools...)
+}
+
+// findK8sNodePool returns the index of the node pool with the given name
+func findK8sNodePool(pools []interface{}, name string) (int, bool) {
+	for i, pool := range pools {
+		if poolMap, ok := pool.(map[string]interface{}); ok && poolMap["name"] == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// mergeK8sNodePools replaces the node pools, matched by name, with the requested ones. Pools which
+// are managed (declared before) but not requested anymore are removed, all other pools (e.g. pools
+// of gridscale_k8s_node_pool resources) are kept. New pools are appended.
+func mergeK8sNodePools(pools []interface{}, requested []map[string]interface{}, managed map[string]bool) []interface{} {
+	merged := make([]interface{}, 0, len(pools)+len(requested))
+	used := make(map[int]bool)
+	for _, pool := range pools {
+		poolMap, _ := pool.(map[string]interface{})
+		name, _ := poolMap["name"].(string)
+		index := -1
+		for i, requestedPool := range requested {
+			if requestedPool["name"] == name {
+				index = i
+				break
+			}
+		}
+		if index >= 0 {
+			merged = append(merged, requested[index])
+			used[index] = true
+		} else if !managed[name] {
+			merged = append(merged, pool)
+		}
+	}
+	for i, requestedPool := range requested {
+		if !used[i] {
+			merged = append(merged, requestedPool)
+		}
+	}
+	return merged
+}
+
+// expandK8sNodePool converts the attributes of a node pool to an element of the `pools` parameter
+func expandK8sNodePool(get func(key string) interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           get("name"),
+		"count":          get("node_count"),
+		"ram":            get("memory"),
+		"cores":          get("cores"),
+		"storage":        get("storage"),
+		"storage_type":   get("storage_type"),
+		"rocket_storage": get("rocket_storage"),
+	}
+}
+
+// flattenK8sNodePool converts an element of the `pools` parameter to the attributes of a node pool
+func flattenK8sNodePool(nodePoolSet map[string]interface{}) map[string]interface{} {
+	nodePoolRead := make(map[string]interface{}, 0)
+	if name, isNameSet := nodePoolSet["name"]; isNameSet {
+		nodePoolRead["name"] = name
+	}
+	if nodeCount, isNodeCountSet := nodePoolSet["count"]; isNodeCountSet {
+		nodePoolRead["node_count"] = nodeCount
+	}
+	if memory, isMemorySet := nodePoolSet["ram"]; isMemorySet {
+		nodePoolRead["memory"] = memory
+	}
+	if cores, isCoresSet := nodePoolSet["cores"]; isCoresSet {
+		nodePoolRead["cores"] = cores
+	}
+	if storage, isStorageSet := nodePoolSet["storage"]; isStorageSet {
+		nodePoolRead["storage"] = storage
+	}
+	if storageType, isStorageTypeSet := nodePoolSet["storage_type"]; isStorageTypeSet {
+		nodePoolRead["storage_type"] = storageType
+	}
+	if rocketStorage, isRocketStorageSet := nodePoolSet["rocket_storage"]; isRocketStorageSet {
+		nodePoolRead["rocket_storage"] = rocketStorage
+	}
+	return nodePoolRead
+}
@@ -0,0 +1,153 @@
+package gridscale
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestMergeK8sNodePools(t *testing.T) {
+	pool := func(name string, count int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "count": count}
+	}
+	tests := []struct {
+		name      string
+		pools     []interface{}
+		requested []map[string]interface{}
+		managed   map[string]bool
+		expected  []interface{}
+	}{
+		{
+			name:      "reordered pools keep their positions",
+			pools:     []interface{}{pool("a", 1), pool("b", 1)},
+			requested: []map[string]interface{}{pool("b", 2), pool("a", 1)},
+			managed:   map[string]bool{"a": true, "b": true},
+			expected:  []interface{}{pool("a", 1), pool("b", 2)},
+		},
+		{
+			name:      "pools of other resources are kept",
+			pools:     []interface{}{pool("a", 1), pool("gpu", 1)},
+			requested: []map[string]interface{}{pool("a", 3)},
+			managed:   map[string]bool{"a": true},
+			expected:  []interface{}{pool("a", 3), pool("gpu", 1)},
+		},
+		{
+			name:      "removed pools are dropped and new pools appended",
+			pools:     []interface{}{pool("a", 1), pool("b", 1), pool("gpu", 1)},
+			requested: []map[string]interface{}{pool("c", 1), pool("a", 1)},
+			managed:   map[string]bool{"a": true, "b": true},
+			expected:  []interface{}{pool("a", 1), pool("gpu", 1), pool("c", 1)},
+		},
+		{
+			name:      "imported pools of node pool resources are kept",
+			pools:     []interface{}{pool("a", 1), pool("gpu", 1)},
+			requested: []map[string]interface{}{pool("a", 2)},
+			managed: managedK8sNodePools(
+				[]interface{}{pool("a", 1), pool("gpu", 1)},
+				[]string{"env:prod", k8sNodePoolLabelPrefix + "gpu"},
+			),
+			expected: []interface{}{pool("a", 2), pool("gpu", 1)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := mergeK8sNodePools(test.pools, test.requested, test.managed)
+			if !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, merged)
+			}
+		})
+	}
+}
+
+func TestSetK8sNodePoolLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		managed  bool
+		expected []string
+		changed  bool
+	}{
+		{"mark is added", []string{"env:prod"}, true, []string{"env:prod", k8sNodePoolLabelPrefix + "gpu"}, true},
+		{"mark is kept", []string{k8sNodePoolLabelPrefix + "gpu", "env:prod"}, true, []string{"env:prod", k8sNodePoolLabelPrefix + "gpu"}, false},
+		{"mark is removed", []string{k8sNodePoolLabelPrefix + "gpu", k8sNodePoolLabelPrefix + "batch"}, false, []string{k8sNodePoolLabelPrefix + "batch"}, true},
+		{"missing mark is not removed", []string{"env:prod"}, false, []string{"env:prod"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			labels, changed := setK8sNodePoolLabel(test.labels, "gpu", test.managed)
+			if !reflect.DeepEqual(labels, test.expected) || changed != test.changed {
+				t.Errorf("expected (%v, %v), got (%v, %v)", test.expected, test.changed, labels, changed)
+			}
+		})
+	}
+}
+
+func TestAccResourceGridscaleK8sNodePoolBasic(t *testing.T) {
+	var object gsclient.PaaSService
+	name := fmt.Sprintf("k8s-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceGridscalePaaSDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleK8sNodePoolConfigBasic(name, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscalePaaSExists("gridscale_k8s.foopaas", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s_node_pool.foo", "name", "extra-pool"),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s_node_pool.foo", "node_count", "1"),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s_node_pool.foo", "storage_type", "storage_insane"),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s.foopaas", "node_pool.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckResourceGridscaleK8sNodePoolConfigBasic(name, 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscalePaaSExists("gridscale_k8s.foopaas", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s_node_pool.foo", "node_count", "2"),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s.foopaas", "node_pool.#", "1"),
+					resource.TestCheckResourceAttr(
+						"gridscale_k8s.foopaas", "node_pool.0.name", "my-node-pool"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceGridscaleK8sNodePoolConfigBasic(name string, nodeCount int) string {
+	return fmt.Sprintf(`
+resource "gridscale_k8s" "foopaas" {
+	name   = "%s"
+	release = "1.30"
+	node_pool {
+		name = "my-node-pool"
+		node_count = 1
+		cores = 1
+		memory = 2
+		storage = 30
+		storage_type = "storage_insane"
+	}
+}
+
+resource "gridscale_k8s_node_pool" "foo" {
+	cluster_id = gridscale_k8s.foopaas.id
+	name = "extra-pool"
+	node_count = %d
+	cores = 1
+	memory = 2
+	storage = 30
+	storage_type = "storage_insane"
+}
+`, name, nodeCount)
+}
@@ -2,10 +2,13 @@ package gridscale
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/gridscale/gsclient-go/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"testing"
 )
@@ -356,3 +359,88 @@ func testAccCheckResourceGridscaleK8sConfigRemoveNodePool() string {
 	}
 	`
 }
+
+func TestAccResourceGridscaleK8sKubeconfigFileRenewal(t *testing.T) {
+	name := fmt.Sprintf("k8s-%s", acctest.RandString(10))
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	var firstContent string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceGridscalePaaSDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				// the threshold renews the kubeconfig on every refresh, so the plan after the apply is not empty
+				Config: testAccCheckResourceGridscaleK8sConfigKubeconfigFile(name, path),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleK8sKubeconfigFileWritten("gridscale_k8s.foopaas", path, &firstContent),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// the kubeconfig renewed on refresh is written by the apply
+				Config: testAccCheckResourceGridscaleK8sConfigKubeconfigFile(name, path),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleK8sKubeconfigFileWritten("gridscale_k8s.foopaas", path, nil),
+					func(s *terraform.State) error {
+						content, err := os.ReadFile(path)
+						if err != nil {
+							return err
+						}
+						if string(content) == firstContent {
+							return fmt.Errorf("kubeconfig file %s has not been rewritten after the renewal", path)
+						}
+						return nil
+					},
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccCheckResourceGridscaleK8sKubeconfigFileWritten checks that the kubeconfig in state has been written
+// to the kubeconfig file. The content of the file is stored in content, if it is not nil.
+func testAccCheckResourceGridscaleK8sKubeconfigFileWritten(n, path string, content *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		attrs := rs.Primary.Attributes
+		if expected := k8sKubeconfigSHA256(attrs["kubeconfig"]); attrs["kubeconfig_file_sha256"] != expected {
+			return fmt.Errorf("expected kubeconfig_file_sha256 %s, got %s", expected, attrs["kubeconfig_file_sha256"])
+		}
+		fileContent, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading kubeconfig file %s: %v", path, err)
+		}
+		if content != nil {
+			*content = string(fileContent)
+		}
+		return nil
+	}
+}
+
+func testAccCheckResourceGridscaleK8sConfigKubeconfigFile(name, path string) string {
+	return fmt.Sprintf(`
+resource "gridscale_k8s" "foopaas" {
+	name   = "%s"
+	release = "1.30"
+	credential_renewal_threshold = "87600h"
+	node_pool {
+		name = "my-node-pool"
+		node_count = 1
+		cores = 1
+		memory = 2
+		storage = 30
+		storage_type = "storage_insane"
+	}
+	kubeconfig_file {
+		path = "%s"
+		merge = false
+	}
+}
+`, name, path)
+}
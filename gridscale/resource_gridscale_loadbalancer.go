@@ -3,7 +3,9 @@ package gridscale
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +24,29 @@ func resourceGridscaleLoadBalancer() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			client := meta.(*gsclient.Client)
+			if !d.NewValueKnown("backend_selector") || !d.NewValueKnown("backend_server") {
+				return d.SetNewComputed("discovered_backend_server")
+			}
+			selectors := d.Get("backend_selector").([]interface{})
+			oldDiscovered := d.Get("discovered_backend_server").(*schema.Set)
+			if len(selectors) == 0 && oldDiscovered.Len() == 0 {
+				return nil
+			}
+			// Resolve the selectors on every plan, so that added or removed servers show up in the plan
+			discovered, err := discoverLoadbalancerBackendServers(ctx, client, selectors)
+			if err != nil {
+				return fmt.Errorf("error discovering backend servers: %v", err)
+			}
+			// declared backend servers take precedence, they are not shown as discovered
+			discovered = removeLoadbalancerStaticHosts(discovered, d.Get("backend_server").(*schema.Set).List())
+			newDiscovered := schema.NewSet(oldDiscovered.F, discovered)
+			if !newDiscovered.Equal(oldDiscovered) {
+				return d.SetNew("discovered_backend_server", discovered)
+			}
+			return nil
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -88,9 +113,10 @@ func resourceGridscaleLoadBalancer() *schema.Resource {
 				},
 			},
 			"backend_server": {
-				Type:        schema.TypeSet,
-				Description: "List of backend servers.",
-				Required:    true,
+				Type:         schema.TypeSet,
+				Description:  "List of backend servers.",
+				Optional:     true,
+				AtLeastOneOf: []string{"backend_server", "backend_selector"},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"weight": {
@@ -109,6 +135,84 @@ func resourceGridscaleLoadBalancer() *schema.Resource {
 					},
 				},
 			},
+			"backend_selector": {
+				Type:         schema.TypeList,
+				Description:  "Selectors for backend servers, which are resolved through the server list at plan time.",
+				Optional:     true,
+				AtLeastOneOf: []string{"backend_server", "backend_selector"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"labels": {
+							Type:        schema.TypeSet,
+							Description: "Servers having all of these labels are selected.",
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"location_uuid": {
+							Type:        schema.TypeString,
+							Description: "Only servers in this location are selected.",
+							Optional:    true,
+						},
+						"network_uuid": {
+							Type:        schema.TypeString,
+							Description: "If set, the DHCP IP of the servers in this network is used as host. Otherwise the public IP of the servers is used.",
+							Optional:    true,
+						},
+						"ip_family": {
+							Type:         schema.TypeInt,
+							Description:  "The family (4 or 6) of the public IP which is used as host, if network_uuid is not set.",
+							Optional:     true,
+							Default:      4,
+							ValidateFunc: validation.IntInSlice([]int{4, 6}),
+						},
+						"running_only": {
+							Type:        schema.TypeBool,
+							Description: "Only servers which are powered on are selected.",
+							Optional:    true,
+							Default:     true,
+						},
+						"weight": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  100,
+						},
+						"proxy_protocol": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"discovered_backend_server": {
+				Type:        schema.TypeSet,
+				Description: "Backend servers resolved from backend_selector. Hosts which are also declared in backend_server are not included.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"weight": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"proxy_protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"status": {
 				Type:        schema.TypeString,
 				Description: "Status indicates the status of the object.",
@@ -162,9 +266,7 @@ func resourceGridscaleLoadBalancerCreate(d *schema.ResourceData, meta interface{
 		requestBody.Algorithm = gsclient.LoadbalancerLeastConnAlg
 	}
 
-	if backendServers, ok := d.GetOk("backend_server"); ok {
-		requestBody.BackendServers = expandLoadbalancerBackendServers(backendServers)
-	}
+	requestBody.BackendServers = expandLoadbalancerBackendServersWithDiscovered(d)
 	if forwardingRules, ok := d.GetOk("forwarding_rule"); ok {
 		requestBody.ForwardingRules = expandLoadbalancerForwardingRules(forwardingRules)
 	}
@@ -221,9 +323,13 @@ func resourceGridscaleLoadBalancerRead(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("%s error setting forwarding_rule: %v", errorPrefix, err)
 	}
 
-	if err = d.Set("backend_server", flattenLoadbalancerBackendServers(loadbalancer.Properties.BackendServers)); err != nil {
+	staticBackendServers, discoveredBackendServers := splitLoadbalancerBackendServers(d, loadbalancer.Properties.BackendServers)
+	if err = d.Set("backend_server", flattenLoadbalancerBackendServers(staticBackendServers)); err != nil {
 		return fmt.Errorf("%s error setting backend_server: %v", errorPrefix, err)
 	}
+	if err = d.Set("discovered_backend_server", discoveredBackendServers); err != nil {
+		return fmt.Errorf("%s error setting discovered_backend_server: %v", errorPrefix, err)
+	}
 
 	if err = d.Set("labels", loadbalancer.Properties.Labels); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
@@ -249,9 +355,7 @@ func resourceGridscaleLoadBalancerUpdate(d *schema.ResourceData, meta interface{
 		requestBody.Algorithm = gsclient.LoadbalancerLeastConnAlg
 	}
 
-	if backendServers, ok := d.GetOk("backend_server"); ok {
-		requestBody.BackendServers = expandLoadbalancerBackendServers(backendServers)
-	}
+	requestBody.BackendServers = expandLoadbalancerBackendServersWithDiscovered(d)
 	if forwardingRules, ok := d.GetOk("forwarding_rule"); ok {
 		requestBody.ForwardingRules = expandLoadbalancerForwardingRules(forwardingRules)
 	}
@@ -299,6 +403,173 @@ func expandLoadbalancerBackendServers(backendServers interface{}) []gsclient.Bac
 	return tempBackendServers
 }
 
+// expandLoadbalancerBackendServersWithDiscovered merges the declared backend servers
+// and the backend servers discovered by the backend selectors. Declared backend
+// servers take precedence over discovered ones with the same host.
+func expandLoadbalancerBackendServersWithDiscovered(d *schema.ResourceData) []gsclient.BackendServer {
+	backendServers := expandLoadbalancerBackendServers(d.Get("backend_server"))
+	staticHosts := make(map[string]bool)
+	for _, backendServer := range backendServers {
+		staticHosts[backendServer.Host] = true
+	}
+	for _, value := range d.Get("discovered_backend_server").(*schema.Set).List() {
+		server := value.(map[string]interface{})
+		if staticHosts[server["host"].(string)] {
+			continue
+		}
+		backendServer := gsclient.BackendServer{
+			Weight: server["weight"].(int),
+			Host:   server["host"].(string),
+		}
+		proxyProtocol := server["proxy_protocol"].(string)
+		if proxyProtocol != "" {
+			backendServer.ProxyProtocol = &proxyProtocol
+		}
+		backendServers = append(backendServers, backendServer)
+	}
+	return backendServers
+}
+
+// splitLoadbalancerBackendServers splits the backend servers of a load balancer into
+// declared ones and the ones which were added from discovered_backend_server
+func splitLoadbalancerBackendServers(d *schema.ResourceData, backendServers []gsclient.BackendServer) ([]gsclient.BackendServer, []interface{}) {
+	staticHosts := make(map[string]bool)
+	for _, value := range d.Get("backend_server").(*schema.Set).List() {
+		staticHosts[value.(map[string]interface{})["host"].(string)] = true
+	}
+	discoveredByHost := make(map[string]map[string]interface{})
+	for _, value := range d.Get("discovered_backend_server").(*schema.Set).List() {
+		server := value.(map[string]interface{})
+		discoveredByHost[server["host"].(string)] = server
+	}
+	var static []gsclient.BackendServer
+	discovered := make([]interface{}, 0)
+	for _, backendServer := range backendServers {
+		server, ok := discoveredByHost[backendServer.Host]
+		if !ok || staticHosts[backendServer.Host] {
+			static = append(static, backendServer)
+			continue
+		}
+		var proxyProtocol string
+		if backendServer.ProxyProtocol != nil {
+			proxyProtocol = *backendServer.ProxyProtocol
+		}
+		discovered = append(discovered, map[string]interface{}{
+			"host":           backendServer.Host,
+			"weight":         backendServer.Weight,
+			"proxy_protocol": proxyProtocol,
+			"server_uuid":    server["server_uuid"],
+			"server_name":    server["server_name"],
+		})
+	}
+	return static, discovered
+}
+
+// removeLoadbalancerStaticHosts removes the discovered backend servers, whose hosts are
+// declared in backend_server
+func removeLoadbalancerStaticHosts(discovered []interface{}, backendServers []interface{}) []interface{} {
+	staticHosts := make(map[string]bool)
+	for _, value := range backendServers {
+		staticHosts[value.(map[string]interface{})["host"].(string)] = true
+	}
+	result := make([]interface{}, 0, len(discovered))
+	for _, value := range discovered {
+		if !staticHosts[value.(map[string]interface{})["host"].(string)] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// discoverLoadbalancerBackendServers resolves backend selectors to backend servers
+// through the server list. A server matched by several selectors is added once,
+// with the weight of the first matching selector.
+func discoverLoadbalancerBackendServers(ctx context.Context, client *gsclient.Client, selectors []interface{}) ([]interface{}, error) {
+	discovered := make([]interface{}, 0)
+	if len(selectors) == 0 {
+		return discovered, nil
+	}
+	servers, err := client.GetServerList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Properties.ObjectUUID < servers[j].Properties.ObjectUUID })
+	addedHosts := make(map[string]bool)
+	for idx, selectorIntf := range selectors {
+		selector := selectorIntf.(map[string]interface{})
+		labels := convSOStrings(selector["labels"].(*schema.Set).List())
+		locationUUID := selector["location_uuid"].(string)
+		networkUUID := selector["network_uuid"].(string)
+		ipFamily := selector["ip_family"].(int)
+		runningOnly := selector["running_only"].(bool)
+
+		// DHCP IPs of the servers in the selected network
+		var networkIPs map[string]string
+		if networkUUID != "" {
+			network, err := client.GetNetwork(ctx, networkUUID)
+			if err != nil {
+				return nil, fmt.Errorf("backend_selector.%d: error getting network (%s): %v", idx, networkUUID, err)
+			}
+			networkIPs = make(map[string]string)
+			for _, server := range network.Properties.AutoAssignedServers {
+				networkIPs[server.ServerUUID] = server.IP
+			}
+			for _, server := range network.Properties.PinnedServers {
+				networkIPs[server.ServerUUID] = server.IP
+			}
+		}
+
+		for _, server := range servers {
+			props := server.Properties
+			if !hasAllLabels(props.Labels, labels) ||
+				(locationUUID != "" && props.LocationUUID != locationUUID) ||
+				(runningOnly && !props.Power) {
+				continue
+			}
+			var host string
+			if networkIPs != nil {
+				host = networkIPs[props.ObjectUUID]
+			} else {
+				for _, ip := range props.Relations.PublicIPs {
+					if ip.Family == ipFamily {
+						host = ip.IP
+					}
+				}
+			}
+			if host == "" {
+				log.Printf("[WARN] backend_selector.%d: server %s (%s) has no matching IP address and is skipped", idx, props.Name, props.ObjectUUID)
+				continue
+			}
+			if addedHosts[host] {
+				continue
+			}
+			addedHosts[host] = true
+			discovered = append(discovered, map[string]interface{}{
+				"host":           host,
+				"weight":         selector["weight"].(int),
+				"proxy_protocol": selector["proxy_protocol"].(string),
+				"server_uuid":    props.ObjectUUID,
+				"server_name":    props.Name,
+			})
+		}
+	}
+	return discovered, nil
+}
+
+// hasAllLabels checks if all wanted labels are in labels
+func hasAllLabels(labels, wanted []string) bool {
+	labelSet := make(map[string]bool)
+	for _, label := range labels {
+		labelSet[label] = true
+	}
+	for _, label := range wanted {
+		if !labelSet[label] {
+			return false
+		}
+	}
+	return true
+}
+
 func expandLoadbalancerForwardingRules(forwardingRules interface{}) []gsclient.ForwardingRule {
 	tempForwardingRules := []gsclient.ForwardingRule{}
 	for _, value := range forwardingRules.(*schema.Set).List() {
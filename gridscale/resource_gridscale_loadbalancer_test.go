@@ -3,6 +3,7 @@ package gridscale
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -47,6 +48,49 @@ func TestAccResourceGridscaleLoadBalancerBasic(t *testing.T) {
 	})
 }
 
+func TestRemoveLoadbalancerStaticHosts(t *testing.T) {
+	server := func(host string) map[string]interface{} {
+		return map[string]interface{}{"host": host, "weight": 100}
+	}
+	discovered := []interface{}{server("185.201.147.10"), server("185.201.147.11")}
+	backendServers := []interface{}{server("185.201.147.11"), server("185.201.147.12")}
+	expected := []interface{}{server("185.201.147.10")}
+	if result := removeLoadbalancerStaticHosts(discovered, backendServers); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestAccResourceGridscaleLoadBalancerBackendSelector(t *testing.T) {
+	var object gsclient.LoadBalancer
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleLoadBalancerDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				// The backend servers have to exist before they can be discovered at plan time
+				Config: testAccCheckResourceGridscaleLoadBalancerConfigBackendServers(name),
+			},
+			{
+				Config: testAccCheckResourceGridscaleLoadBalancerConfigBackendServers(name) +
+					testAccCheckResourceGridscaleLoadBalancerConfigBackendSelector(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleLoadBalancerExists("gridscale_loadbalancer.foo", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_loadbalancer.foo", "backend_server.#", "0"),
+					resource.TestCheckResourceAttr(
+						"gridscale_loadbalancer.foo", "discovered_backend_server.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(
+						"gridscale_loadbalancer.foo", "discovered_backend_server.*.host",
+						"gridscale_ipv4.server", "ip"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckResourceGridscaleLoadBalancerExists(n string, object *gsclient.LoadBalancer) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -165,3 +209,53 @@ func testAccCheckGridscaleLoadBalancerDestroyCheck(s *terraform.State) error {
 
 	return nil
 }
+
+func testAccCheckResourceGridscaleLoadBalancerConfigBackendServers(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_ipv4" "server" {
+	name   = "server-%s"
+}
+resource "gridscale_server" "backend" {
+	name   = "%s"
+	cores = 1
+	memory = 1
+	power = true
+	ipv4 = gridscale_ipv4.server.id
+	labels = ["lb-%s"]
+}
+resource "gridscale_server" "other" {
+	name   = "other-%s"
+	cores = 1
+	memory = 1
+	power = true
+}
+`, name, name, name, name)
+}
+
+func testAccCheckResourceGridscaleLoadBalancerConfigBackendSelector(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_ipv4" "lb" {
+	name   = "ipv4-%s"
+}
+resource "gridscale_ipv6" "lb" {
+	name   = "ipv6-%s"
+}
+resource "gridscale_loadbalancer" "foo" {
+	name   = "%s"
+	algorithm = "roundrobin"
+	redirect_http_to_https = false
+	listen_ipv4_uuid = gridscale_ipv4.lb.id
+	listen_ipv6_uuid = gridscale_ipv6.lb.id
+	labels = []
+	backend_selector {
+		labels = ["lb-%s"]
+		weight = 50
+	}
+	forwarding_rule {
+		listen_port =  80
+		mode        =  "http"
+		target_port =  80
+	}
+}
+`, name, name, name, name)
+}
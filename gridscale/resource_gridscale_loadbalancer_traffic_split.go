@@ -0,0 +1,418 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxTrafficSplitWeight is the weight of the backend server which receives the
+// largest share of the traffic, the weights of the other backends are scaled
+// accordingly.
+const maxTrafficSplitWeight = 100
+
+func resourceGridscaleLoadBalancerTrafficSplit() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridscaleLoadBalancerTrafficSplitCreate,
+		Read:   resourceGridscaleLoadBalancerTrafficSplitRead,
+		Update: resourceGridscaleLoadBalancerTrafficSplitUpdate,
+		Delete: resourceGridscaleLoadBalancerTrafficSplitDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if !d.NewValueKnown("backend_group") {
+				return nil
+			}
+			groupNames := make(map[string]bool)
+			groupOfHost := make(map[string]string)
+			var sum int
+			for _, value := range d.Get("backend_group").([]interface{}) {
+				group := value.(map[string]interface{})
+				name := group["name"].(string)
+				if groupNames[name] {
+					return fmt.Errorf("backend group %q is declared more than once", name)
+				}
+				groupNames[name] = true
+				for _, server := range group["backend_server"].(*schema.Set).List() {
+					host := server.(map[string]interface{})["host"].(string)
+					if other, ok := groupOfHost[host]; ok {
+						return fmt.Errorf("host %s is in backend group %q and %q, a host can only be in one backend group", host, other, name)
+					}
+					groupOfHost[host] = name
+				}
+				sum += group["percentage"].(int)
+			}
+			if sum != 100 {
+				return fmt.Errorf("the percentages of the backend groups sum up to %d, they have to sum up to 100", sum)
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			"loadbalancer_uuid": {
+				Type:         schema.TypeString,
+				Description:  "The UUID of the load balancer whose backend servers are managed.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"backend_group": {
+				Type:        schema.TypeList,
+				Description: "Named groups of backend servers (e.g. blue, green, canary) and the percentage of the traffic they receive.",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Description:  "The name of the backend group.",
+							Required:     true,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"percentage": {
+							Type:         schema.TypeInt,
+							Description:  "The percentage of the traffic the backend group receives. The percentages of all backend groups have to sum up to 100.",
+							Required:     true,
+							ValidateFunc: validation.IntBetween(0, 100),
+						},
+						"backend_server": {
+							Type:        schema.TypeSet,
+							Description: "The backend servers of the group. The traffic of the group is split evenly between them.",
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"host": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.NoZeroValues,
+									},
+									"proxy_protocol": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"step_percentage": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum percentage of the traffic which is shifted between backend groups in one step.",
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"step_interval": {
+				Type:         schema.TypeString,
+				Description:  "The time to wait between two steps, e.g. \"5m\".",
+				Optional:     true,
+				Default:      "0s",
+				ValidateFunc: validateDuration,
+			},
+			"health_check": {
+				Type:        schema.TypeBool,
+				Description: "Whether the target ports of the forwarding rules of the load balancer are checked on every backend server which receives traffic, after every step.",
+				Optional:    true,
+				Default:     true,
+			},
+			"health_check_timeout": {
+				Type:         schema.TypeString,
+				Description:  "The time backend servers have to become reachable on their target ports after a step, e.g. \"2m\".",
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+			},
+			"backend_weights": {
+				Type:        schema.TypeMap,
+				Description: "The weights of the backend servers of the load balancer, by host.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// validateDuration validates a duration string, e.g. "5m"
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%s is not a valid duration: %v", k, err))
+	} else if duration < 0 {
+		errors = append(errors, fmt.Errorf("%s must not be negative", k))
+	}
+	return
+}
+
+func resourceGridscaleLoadBalancerTrafficSplitCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(d.Get("loadbalancer_uuid").(string))
+	log.Printf("[DEBUG] The traffic split of loadbalancer %s is managed now", d.Id())
+	return resourceGridscaleLoadBalancerTrafficSplitApply(d, meta, d.Timeout(schema.TimeoutCreate))
+}
+
+func resourceGridscaleLoadBalancerTrafficSplitRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("read loadbalancer traffic split (%s) resource -", d.Id())
+
+	loadbalancer, err := client.GetLoadBalancer(context.Background(), d.Id())
+	if err != nil {
+		if requestError, ok := err.(gsclient.RequestError); ok {
+			if requestError.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+
+	if err = d.Set("loadbalancer_uuid", loadbalancer.Properties.ObjectUUID); err != nil {
+		return fmt.Errorf("%s error setting loadbalancer_uuid: %v", errorPrefix, err)
+	}
+	weights := make(map[string]interface{})
+	for _, backendServer := range loadbalancer.Properties.BackendServers {
+		weights[backendServer.Host] = backendServer.Weight
+	}
+	if err = d.Set("backend_weights", weights); err != nil {
+		return fmt.Errorf("%s error setting backend_weights: %v", errorPrefix, err)
+	}
+
+	// Set the percentages of the groups from the actual weights. Differences
+	// of one percent are caused by rounding the weights and are ignored.
+	shares := trafficSplitSharesFromWeights(loadbalancer.Properties.BackendServers)
+	groups := d.Get("backend_group").([]interface{})
+	for _, value := range groups {
+		group := value.(map[string]interface{})
+		var share float64
+		for _, server := range group["backend_server"].(*schema.Set).List() {
+			share += shares[server.(map[string]interface{})["host"].(string)]
+		}
+		percentage := int(math.Round(share * 100))
+		if math.Abs(float64(percentage-group["percentage"].(int))) > 1 {
+			group["percentage"] = percentage
+		}
+	}
+	if err = d.Set("backend_group", groups); err != nil {
+		return fmt.Errorf("%s error setting backend_group: %v", errorPrefix, err)
+	}
+	return nil
+}
+
+func resourceGridscaleLoadBalancerTrafficSplitUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceGridscaleLoadBalancerTrafficSplitApply(d, meta, d.Timeout(schema.TimeoutUpdate))
+}
+
+func resourceGridscaleLoadBalancerTrafficSplitDelete(d *schema.ResourceData, meta interface{}) error {
+	// The backend servers are left as they are, deleting the load balancer is up to the gridscale_loadbalancer resource
+	log.Printf("[DEBUG] The traffic split of loadbalancer %s is not managed anymore, its backend servers are kept", d.Id())
+	return nil
+}
+
+// resourceGridscaleLoadBalancerTrafficSplitApply shifts the traffic of the load balancer stepwise to the configured split
+func resourceGridscaleLoadBalancerTrafficSplitApply(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("apply loadbalancer traffic split (%s) resource -", d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	loadbalancer, err := client.GetLoadBalancer(ctx, d.Id())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	stepInterval, _ := time.ParseDuration(d.Get("step_interval").(string))
+	healthCheckTimeout, _ := time.ParseDuration(d.Get("health_check_timeout").(string))
+
+	split := expandTrafficSplit(d.Get("backend_group").([]interface{}), loadbalancer.Properties.BackendServers)
+	steps := split.steps(d.Get("step_percentage").(int))
+	for i, shares := range steps {
+		backendServers := split.backendServers(shares)
+		log.Printf("[DEBUG] Traffic split step %d/%d of loadbalancer %s: %v", i+1, len(steps), d.Id(), backendServers)
+		err = client.UpdateLoadBalancer(ctx, d.Id(), gsclient.LoadBalancerUpdateRequest{
+			Name:                loadbalancer.Properties.Name,
+			ListenIPv6UUID:      loadbalancer.Properties.ListenIPv6UUID,
+			ListenIPv4UUID:      loadbalancer.Properties.ListenIPv4UUID,
+			Algorithm:           gsclient.LoadbalancerAlgorithm(loadbalancer.Properties.Algorithm),
+			ForwardingRules:     loadbalancer.Properties.ForwardingRules,
+			BackendServers:      backendServers,
+			Labels:              loadbalancer.Properties.Labels,
+			RedirectHTTPToHTTPS: loadbalancer.Properties.RedirectHTTPToHTTPS,
+		})
+		if err != nil {
+			return resourceGridscaleLoadBalancerTrafficSplitStepError(d, meta, fmt.Errorf("%s error in step %d/%d: %v", errorPrefix, i+1, len(steps), err))
+		}
+		if d.Get("health_check").(bool) {
+			err = checkLoadbalancerBackendServers(ctx, backendServers, loadbalancer.Properties.ForwardingRules, healthCheckTimeout)
+			if err != nil {
+				return resourceGridscaleLoadBalancerTrafficSplitStepError(d, meta, fmt.Errorf("%s health check after step %d/%d failed: %v", errorPrefix, i+1, len(steps), err))
+			}
+		}
+		if i < len(steps)-1 && stepInterval > 0 {
+			select {
+			case <-time.After(stepInterval):
+			case <-ctx.Done():
+				return resourceGridscaleLoadBalancerTrafficSplitStepError(d, meta, fmt.Errorf("%s timeout before step %d/%d: %v", errorPrefix, i+2, len(steps), ctx.Err()))
+			}
+		}
+	}
+	return resourceGridscaleLoadBalancerTrafficSplitRead(d, meta)
+}
+
+// resourceGridscaleLoadBalancerTrafficSplitStepError reads the actual split into the state before returning the error of a step
+func resourceGridscaleLoadBalancerTrafficSplitStepError(d *schema.ResourceData, meta interface{}, err error) error {
+	if readErr := resourceGridscaleLoadBalancerTrafficSplitRead(d, meta); readErr != nil {
+		log.Printf("[WARN] %v", readErr)
+	}
+	return err
+}
+
+// checkLoadbalancerBackendServers checks that the target ports of the forwarding rules are reachable on all backend servers
+func checkLoadbalancerBackendServers(ctx context.Context, backendServers []gsclient.BackendServer, forwardingRules []gsclient.ForwardingRule, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	for _, backendServer := range backendServers {
+		for _, rule := range forwardingRules {
+			address := net.JoinHostPort(backendServer.Host, strconv.Itoa(rule.TargetPort))
+			for {
+				conn, err := dialer.DialContext(ctx, "tcp", address)
+				if err == nil {
+					conn.Close()
+					break
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("%s is not reachable: %v", address, err)
+				}
+				log.Printf("[DEBUG] %s is not reachable yet: %v", address, err)
+				select {
+				case <-time.After(5 * time.Second):
+				case <-ctx.Done():
+					return fmt.Errorf("%s is not reachable: %v", address, ctx.Err())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// trafficSplit holds the current and the target share of the traffic of every
+// backend server of a load balancer. Backend servers which are not in any
+// backend group are moved out of the load balancer.
+type trafficSplit struct {
+	hosts          []string
+	groupOfHost    map[string]string
+	proxyProtocols map[string]*string
+	current        map[string]float64
+	target         map[string]float64
+}
+
+// expandTrafficSplit builds the traffic split from the backend groups and the current backend servers
+func expandTrafficSplit(backendGroups []interface{}, backendServers []gsclient.BackendServer) trafficSplit {
+	split := trafficSplit{
+		groupOfHost:    make(map[string]string),
+		proxyProtocols: make(map[string]*string),
+		current:        trafficSplitSharesFromWeights(backendServers),
+		target:         make(map[string]float64),
+	}
+	for _, backendServer := range backendServers {
+		split.hosts = append(split.hosts, backendServer.Host)
+		split.proxyProtocols[backendServer.Host] = backendServer.ProxyProtocol
+	}
+	for _, value := range backendGroups {
+		group := value.(map[string]interface{})
+		name := group["name"].(string)
+		servers := group["backend_server"].(*schema.Set).List()
+		for _, serverIntf := range servers {
+			server := serverIntf.(map[string]interface{})
+			host := server["host"].(string)
+			if _, ok := split.current[host]; !ok {
+				split.hosts = append(split.hosts, host)
+			}
+			split.groupOfHost[host] = name
+			split.proxyProtocols[host] = nil
+			if proxyProtocol := server["proxy_protocol"].(string); proxyProtocol != "" {
+				split.proxyProtocols[host] = &proxyProtocol
+			}
+			split.target[host] = float64(group["percentage"].(int)) / 100 / float64(len(servers))
+		}
+	}
+	sort.Strings(split.hosts)
+	return split
+}
+
+// trafficSplitSharesFromWeights returns the share of the traffic of every backend server
+func trafficSplitSharesFromWeights(backendServers []gsclient.BackendServer) map[string]float64 {
+	shares := make(map[string]float64)
+	var sum int
+	for _, backendServer := range backendServers {
+		sum += backendServer.Weight
+	}
+	for _, backendServer := range backendServers {
+		shares[backendServer.Host] = 0
+		if sum > 0 {
+			shares[backendServer.Host] = float64(backendServer.Weight) / float64(sum)
+		}
+	}
+	return shares
+}
+
+// steps returns the shares of the backend servers of every step, the last step is the target
+func (s trafficSplit) steps(stepPercentage int) []map[string]float64 {
+	// The number of steps is given by the backend group whose share changes the most
+	groupDelta := make(map[string]float64)
+	for _, host := range s.hosts {
+		groupDelta[s.groupOfHost[host]] += s.target[host] - s.current[host]
+	}
+	var maxDelta float64
+	for _, delta := range groupDelta {
+		maxDelta = math.Max(maxDelta, math.Abs(delta))
+	}
+	numSteps := int(math.Ceil(math.Round(maxDelta*100*1000) / 1000 / float64(stepPercentage)))
+	if numSteps < 1 {
+		numSteps = 1
+	}
+	steps := make([]map[string]float64, numSteps)
+	for i := range steps {
+		progress := float64(i+1) / float64(numSteps)
+		steps[i] = make(map[string]float64)
+		for _, host := range s.hosts {
+			steps[i][host] = s.current[host] + (s.target[host]-s.current[host])*progress
+		}
+	}
+	return steps
+}
+
+// backendServers translates shares to weights of backend servers. Backend servers without traffic are omitted.
+func (s trafficSplit) backendServers(shares map[string]float64) []gsclient.BackendServer {
+	var maxShare float64
+	for _, share := range shares {
+		maxShare = math.Max(maxShare, share)
+	}
+	var backendServers []gsclient.BackendServer
+	for _, host := range s.hosts {
+		share := shares[host]
+		if share <= 1e-9 {
+			continue
+		}
+		weight := int(math.Round(share / maxShare * maxTrafficSplitWeight))
+		if weight < 1 {
+			weight = 1
+		}
+		backendServers = append(backendServers, gsclient.BackendServer{
+			Weight:        weight,
+			Host:          host,
+			ProxyProtocol: s.proxyProtocols[host],
+		})
+	}
+	return backendServers
+}
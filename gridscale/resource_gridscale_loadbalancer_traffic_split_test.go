@@ -0,0 +1,152 @@
+package gridscale
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestTrafficSplitSteps(t *testing.T) {
+	groupSchema := resourceGridscaleLoadBalancerTrafficSplit().Schema["backend_group"].Elem.(*schema.Resource).Schema["backend_server"].Elem.(*schema.Resource)
+	group := func(name string, percentage int, hosts ...string) interface{} {
+		var servers []interface{}
+		for _, host := range hosts {
+			servers = append(servers, map[string]interface{}{"host": host, "proxy_protocol": ""})
+		}
+		return map[string]interface{}{
+			"name":           name,
+			"percentage":     percentage,
+			"backend_server": schema.NewSet(schema.HashResource(groupSchema), servers),
+		}
+	}
+	type testCase struct {
+		Groups          []interface{}
+		Current         []gsclient.BackendServer
+		StepPercentage  int
+		ExpectedWeights [][]gsclient.BackendServer
+	}
+	testCases := []testCase{
+		{
+			Groups:         []interface{}{group("blue", 50, "10.0.0.1"), group("green", 50, "10.0.0.2")},
+			Current:        []gsclient.BackendServer{{Host: "10.0.0.1", Weight: 100}},
+			StepPercentage: 25,
+			ExpectedWeights: [][]gsclient.BackendServer{
+				{{Host: "10.0.0.1", Weight: 100}, {Host: "10.0.0.2", Weight: 33}},
+				{{Host: "10.0.0.1", Weight: 100}, {Host: "10.0.0.2", Weight: 100}},
+			},
+		},
+		{
+			// hosts which are not in a group are moved out, groups with 0 percent are omitted
+			Groups:         []interface{}{group("blue", 0, "10.0.0.1"), group("green", 100, "10.0.0.2", "10.0.0.3")},
+			Current:        []gsclient.BackendServer{{Host: "10.0.0.1", Weight: 50}, {Host: "10.0.0.9", Weight: 50}},
+			StepPercentage: 100,
+			ExpectedWeights: [][]gsclient.BackendServer{
+				{{Host: "10.0.0.2", Weight: 100}, {Host: "10.0.0.3", Weight: 100}},
+			},
+		},
+		{
+			// the split is already reached
+			Groups:         []interface{}{group("blue", 80, "10.0.0.1"), group("green", 20, "10.0.0.2")},
+			Current:        []gsclient.BackendServer{{Host: "10.0.0.1", Weight: 100}, {Host: "10.0.0.2", Weight: 25}},
+			StepPercentage: 10,
+			ExpectedWeights: [][]gsclient.BackendServer{
+				{{Host: "10.0.0.1", Weight: 100}, {Host: "10.0.0.2", Weight: 25}},
+			},
+		},
+	}
+	for _, test := range testCases {
+		split := expandTrafficSplit(test.Groups, test.Current)
+		var weights [][]gsclient.BackendServer
+		for _, shares := range split.steps(test.StepPercentage) {
+			weights = append(weights, split.backendServers(shares))
+		}
+		if !reflect.DeepEqual(weights, test.ExpectedWeights) {
+			t.Errorf("expected weights %+v, got %+v", test.ExpectedWeights, weights)
+		}
+	}
+}
+
+func TestAccResourceGridscaleLoadBalancerTrafficSplitBasic(t *testing.T) {
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleLoadBalancerDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleLoadBalancerTrafficSplitConfig(name, 100, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"gridscale_loadbalancer_traffic_split.foo", "id",
+						"gridscale_loadbalancer.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"gridscale_loadbalancer_traffic_split.foo", "backend_weights.%", "1"),
+				),
+			},
+			{
+				Config: testAccCheckResourceGridscaleLoadBalancerTrafficSplitConfig(name, 50, 50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gridscale_loadbalancer_traffic_split.foo", "backend_group.0.percentage", "50"),
+					resource.TestCheckResourceAttr(
+						"gridscale_loadbalancer_traffic_split.foo", "backend_weights.%", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceGridscaleLoadBalancerTrafficSplitConfig(name string, blue, green int) string {
+	return fmt.Sprintf(`
+resource "gridscale_ipv4" "lb" {
+	name   = "ipv4-%s"
+}
+resource "gridscale_ipv6" "lb" {
+	name   = "ipv6-%s"
+}
+resource "gridscale_loadbalancer" "foo" {
+	name   = "%s"
+	algorithm = "roundrobin"
+	redirect_http_to_https = false
+	listen_ipv4_uuid = gridscale_ipv4.lb.id
+	listen_ipv6_uuid = gridscale_ipv6.lb.id
+	labels = []
+	backend_server {
+		host = "192.0.2.10"
+	}
+	forwarding_rule {
+		listen_port =  80
+		mode        =  "http"
+		target_port =  80
+	}
+	lifecycle {
+		ignore_changes = [backend_server]
+	}
+}
+resource "gridscale_loadbalancer_traffic_split" "foo" {
+	loadbalancer_uuid = gridscale_loadbalancer.foo.id
+	step_percentage = 25
+	health_check = false
+	backend_group {
+		name = "blue"
+		percentage = %d
+		backend_server {
+			host = "192.0.2.10"
+		}
+	}
+	backend_group {
+		name = "green"
+		percentage = %d
+		backend_server {
+			host = "192.0.2.20"
+		}
+	}
+}`, name, name, name, blue, green)
+}
@@ -14,12 +14,16 @@ import (
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
 )
 
+// marketplaceAppSourceFile is uploaded to Object Storage and passed to the API as s3:// path
+var marketplaceAppSourceFile = sourceFileTarget{attribute: "object_storage_path"}
+
 func resourceGridscaleMarketplaceApplication() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceGridscaleMarketplaceApplicationCreate,
-		Read:   resourceGridscaleMarketplaceApplicationRead,
-		Delete: resourceGridscaleMarketplaceApplicationDelete,
-		Update: resourceGridscaleMarketplaceApplicationUpdate,
+	r := &schema.Resource{
+		Create:        resourceGridscaleMarketplaceApplicationCreate,
+		Read:          resourceGridscaleMarketplaceApplicationRead,
+		Delete:        resourceGridscaleMarketplaceApplicationDelete,
+		Update:        resourceGridscaleMarketplaceApplicationUpdate,
+		CustomizeDiff: marketplaceAppSourceFile.customizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -51,7 +55,8 @@ func resourceGridscaleMarketplaceApplication() *schema.Resource {
 			"object_storage_path": {
 				Type:        schema.TypeString,
 				Description: "Path to the images for the application, must be in .gz format and started with s3//",
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 			},
 			"publish": {
 				Type:        schema.TypeBool,
@@ -195,6 +200,8 @@ func resourceGridscaleMarketplaceApplication() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 	}
+	marketplaceAppSourceFile.addSchema(r.Schema)
+	return r
 }
 
 func resourceGridscaleMarketplaceApplicationRead(d *schema.ResourceData, meta interface{}) error {
@@ -355,6 +362,13 @@ func resourceGridscaleMarketplaceApplicationCreate(d *schema.ResourceData, meta
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
 	defer cancel()
+	if _, ok := d.GetOk("source_file"); ok {
+		objectStoragePath, err := marketplaceAppSourceFile.upload(ctx, d)
+		if err != nil {
+			return fmt.Errorf("create marketplace application resource - %v", err)
+		}
+		requestBody.ObjectStoragePath = objectStoragePath
+	}
 	response, err := client.CreateMarketplaceApplication(ctx, requestBody)
 	if err != nil {
 		return err
@@ -410,6 +424,14 @@ func resourceGridscaleMarketplaceApplicationUpdate(d *schema.ResourceData, meta
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
+	// the file is only uploaded again, if its checksum or the object it is uploaded to changed
+	if _, ok := d.GetOk("source_file"); ok && d.HasChanges("source_file_sha256", "object_storage_path") {
+		objectStoragePath, err := marketplaceAppSourceFile.upload(ctx, d)
+		if err != nil {
+			return fmt.Errorf("%s %v", errorPrefix, err)
+		}
+		requestBody.ObjectStoragePath = objectStoragePath
+	}
 	err := client.UpdateMarketplaceApplication(ctx, d.Id(), requestBody)
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
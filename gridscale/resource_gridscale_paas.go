@@ -2,21 +2,24 @@ package gridscale
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+	paasu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/paas-utils"
 
 	"log"
 )
 
 func resourceGridscalePaaS() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceGridscalePaaSServiceCreate,
 		Read:   resourceGridscalePaaSServiceRead,
 		Delete: resourceGridscalePaaSServiceDelete,
@@ -24,6 +27,10 @@ func resourceGridscalePaaS() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customdiff.All(
+			validatePaaSServiceParameters,
+			customizePaaSCredentialsDiff,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -189,6 +196,9 @@ func resourceGridscalePaaS() *schema.Resource {
 			Delete: schema.DefaultTimeout(15 * time.Minute),
 		},
 	}
+	addPaaSConnectionSchema(r.Schema)
+	addPaaSCredentialsSchema(r.Schema)
+	return r
 }
 
 func resourceGridscalePaaSServiceRead(d *schema.ResourceData, meta interface{}) error {
@@ -263,6 +273,9 @@ func resourceGridscalePaaSServiceRead(d *schema.ResourceData, meta interface{})
 	if err = d.Set("listen_port", listenPorts); err != nil {
 		return fmt.Errorf("%s error setting listen ports: %v", errorPrefix, err)
 	}
+	if err = setPaaSConnection(d, props, errorPrefix); err != nil {
+		return err
+	}
 
 	//Get parameters
 	parameters := make([]interface{}, 0)
@@ -344,20 +357,11 @@ func resourceGridscalePaaSServiceCreate(d *schema.ResourceData, meta interface{}
 		requestBody.PaaSSecurityZoneUUID = secZoneUUIDInf.(string)
 	}
 
-	params := make(map[string]interface{}, 0)
-	for _, value := range d.Get("parameter").(*schema.Set).List() {
-		mapVal := value.(map[string]interface{})
-		var param string
-		var val interface{}
-		param = mapVal["param"].(string)
-		paramValType := mapVal["type"].(string)
-		typedVal, err := convStrToTypeInterface(paramValType, mapVal["value"].(string))
-		if err != nil {
-			return err
-		}
-		val = typedVal
-		params[param] = val
+	params, err := paasServiceParameters(d.Get("parameter").(*schema.Set))
+	if err != nil {
+		return err
 	}
+	paasPasswordParameters(d, params)
 	requestBody.Parameters = params
 
 	limits := make([]gsclient.ResourceLimit, 0)
@@ -378,6 +382,9 @@ func resourceGridscalePaaSServiceCreate(d *schema.ResourceData, meta interface{}
 	}
 	d.SetId(response.ObjectUUID)
 	log.Printf("The id for PaaS service %s has been set to %v", requestBody.Name, response.ObjectUUID)
+	if err = rotatePaaSCredentials(ctx, client, d, true); err != nil {
+		return err
+	}
 	return resourceGridscalePaaSServiceRead(d, meta)
 }
 
@@ -403,20 +410,11 @@ func resourceGridscalePaaSServiceUpdate(d *schema.ResourceData, meta interface{}
 		requestBody.PaaSServiceTemplateUUID = d.Get("service_template_uuid").(string)
 	}
 
-	params := make(map[string]interface{}, 0)
-	for _, value := range d.Get("parameter").(*schema.Set).List() {
-		mapVal := value.(map[string]interface{})
-		var param string
-		var val interface{}
-		param = mapVal["param"].(string)
-		paramValType := mapVal["type"].(string)
-		typedVal, err := convStrToTypeInterface(paramValType, mapVal["value"].(string))
-		if err != nil {
-			return fmt.Errorf("%s error: %v", errorPrefix, err)
-		}
-		val = typedVal
-		params[param] = val
+	params, err := paasServiceParameters(d.Get("parameter").(*schema.Set))
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
+	paasPasswordParameters(d, params)
 	requestBody.Parameters = params
 
 	limits := make([]gsclient.ResourceLimit, 0)
@@ -431,10 +429,13 @@ func resourceGridscalePaaSServiceUpdate(d *schema.ResourceData, meta interface{}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
-	err := client.UpdatePaaSService(ctx, d.Id(), requestBody)
+	err = client.UpdatePaaSService(ctx, d.Id(), requestBody)
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
+	if err = rotatePaaSCredentials(ctx, client, d, false); err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
 	return resourceGridscalePaaSServiceRead(d, meta)
 }
 
@@ -453,3 +454,67 @@ func resourceGridscalePaaSServiceDelete(d *schema.ResourceData, meta interface{}
 	}
 	return nil
 }
+
+// paasServiceParameters converts the `parameter` set of a PaaS service to service parameters
+func paasServiceParameters(parameterSet *schema.Set) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, 0)
+	for _, value := range parameterSet.List() {
+		mapVal := value.(map[string]interface{})
+		typedVal, err := convStrToTypeInterface(mapVal["type"].(string), mapVal["value"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'parameter.%s' value: %v", mapVal["param"], err)
+		}
+		params[mapVal["param"].(string)] = typedVal
+	}
+	return params, nil
+}
+
+// validatePaaSServiceParameters validates the free-form parameters of a PaaS service against the
+// parameters schema of its template. Unknown parameters are rejected, as they are ignored by the backend.
+func validatePaaSServiceParameters(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("service_template_uuid") || !d.NewValueKnown("parameter") {
+		return nil
+	}
+	client := meta.(*gsclient.Client)
+	paasTemplates, err := client.GetPaaSTemplateList(ctx)
+	if err != nil {
+		return err
+	}
+	var template *gsclient.PaaSTemplate
+	for i, paasTemplate := range paasTemplates {
+		if paasTemplate.Properties.ObjectUUID == d.Get("service_template_uuid").(string) {
+			template = &paasTemplates[i]
+			break
+		}
+	}
+	// templates which are not listed (e.g. private templates) cannot be validated
+	if template == nil {
+		return nil
+	}
+	params, err := paasServiceParameters(d.Get("parameter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+	opts := paasu.Options{
+		AttributePaths: make(map[string]string),
+		RejectUnknown:  true,
+		CheckRequired:  d.Id() == "",
+	}
+	for param := range params {
+		opts.AttributePaths[param] = fmt.Sprintf("parameter.%s", param)
+	}
+	if d.Id() != "" {
+		oldParameterSet, _ := d.GetChange("parameter")
+		if opts.Current, err = paasServiceParameters(oldParameterSet.(*schema.Set)); err != nil {
+			return err
+		}
+	}
+	errorMessages := paasu.ValidateParameters(template.Properties.ParametersSchema, params, opts)
+	if err = checkPaaSPasswordSupport(d, *template); err != nil {
+		errorMessages = append(errorMessages, err.Error()+"\n")
+	}
+	if len(errorMessages) != 0 {
+		return errors.New(strings.Join(errorMessages, ""))
+	}
+	return nil
+}
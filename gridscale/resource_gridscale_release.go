@@ -48,3 +48,20 @@ func (r *Release) CheckIfK8SReleaseIsSupported() error {
 	}
 	return nil
 }
+
+// CheckIfK8SUpgradeIsAllowed checks by a Release receiver if a Kubernetes cluster of this release can be upgraded to the target release.
+// Downgrades and upgrades skipping a minor release are not allowed.
+func (r *Release) CheckIfK8SUpgradeIsAllowed(target *Release) error {
+	if target.LessThan(&r.Version) {
+		return &ReleaseFeatureIncompatibilityError{
+			Detail: fmt.Sprintf("downgrading a Kubernetes cluster from release %s to %s is not supported", r.Original(), target.Original()),
+		}
+	}
+	current, next := r.Segments(), target.Segments()
+	if next[0] == current[0] && next[1] > current[1]+1 {
+		return &ReleaseFeatureIncompatibilityError{
+			Detail: fmt.Sprintf("upgrading a Kubernetes cluster from release %s to %s skips release %d.%d, a cluster can only be upgraded to the next minor release. Please upgrade to release %d.%d first", r.Original(), target.Original(), current[0], current[1]+1, current[0], current[1]+1),
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -30,6 +31,28 @@ func resourceGridscaleServer() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if !d.NewValueKnown("network") {
+				return d.SetNewComputed("rule_set_conflicts")
+			}
+			for i := range d.Get("network").([]interface{}) {
+				if !firewallRuleSetsKnown(d, fmt.Sprintf("network.%d.rule_sets", i)) {
+					return d.SetNewComputed("rule_set_conflicts")
+				}
+			}
+			conflicts, err := getServerNetworkRuleSetConflicts(d)
+			if err != nil {
+				return err
+			}
+			oldConflicts := convSOStrings(d.Get("rule_set_conflicts").([]interface{}))
+			if len(oldConflicts) == 0 && len(conflicts) == 0 {
+				return nil
+			}
+			if !reflect.DeepEqual(oldConflicts, conflicts) {
+				return d.SetNew("rule_set_conflicts", conflicts)
+			}
+			return nil
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -294,6 +317,15 @@ func resourceGridscaleServer() *schema.Resource {
 								Schema: getFirewallRuleCommonSchema(),
 							},
 						},
+						"rule_sets": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Rule sets (rule_set attribute of gridscale_firewall_rule_set data sources) which are merged, in sequence, after the declared firewall rules of the network.",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateFirewallRuleSet,
+							},
+						},
 						"firewall_template_uuid": {
 							Type:     schema.TypeString,
 							Optional: true,
@@ -315,6 +347,12 @@ func resourceGridscaleServer() *schema.Resource {
 					},
 				},
 			},
+			"rule_set_conflicts": {
+				Type:        schema.TypeList,
+				Description: "Conflicts which were resolved while merging the rule sets of the networks. Rules which match the same traffic as a previous rule are skipped.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"ipv4": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -621,10 +659,31 @@ func resourceGridscaleServerRead(d *schema.ResourceData, meta interface{}) error
 		netWODefaultRules[i].
 			Firewall.RulesV6In = fwu.RemoveDefaultFirewallInboundRules(netWODefaultRules[i].Firewall.RulesV6In)
 	}
+	// Remove the rules merged from rule sets, only the declared rules are displayed
+	netRuleSetConfigs, err := getServerNetworkRuleSetConfigs(d)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	for i := 0; i < len(netWODefaultRules); i++ {
+		if cfg, ok := netRuleSetConfigs[netWODefaultRules[i].ObjectUUID]; ok {
+			netWODefaultRules[i].Firewall = fwu.RemoveRuleSetRules(netWODefaultRules[i].Firewall, cfg.explicitRules, cfg.ruleSets)
+		}
+	}
 	networks, err := readServerNetworkRels(context.Background(), client, d.Id(), netWODefaultRules)
 	if err != nil {
 		return fmt.Errorf("%s error reading server-network relations: %v", errorPrefix, err)
 	}
+	for _, netIntf := range networks {
+		network := netIntf.(map[string]interface{})
+		network["rule_sets"] = netRuleSetConfigs[network["object_uuid"].(string)].ruleSetStrs
+	}
+	conflicts, err := getServerNetworkRuleSetConflicts(d)
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	if err = d.Set("rule_set_conflicts", conflicts); err != nil {
+		return fmt.Errorf("%s error setting rule_set_conflicts: %v", errorPrefix, err)
+	}
 	if err = d.Set("network", networks); err != nil {
 		return fmt.Errorf("%s error setting network: %v", errorPrefix, err)
 	}
@@ -729,6 +788,69 @@ func readServerNetworkRels(ctx context.Context, client *gsclient.Client, serverU
 	return networks, nil
 }
 
+// serverNetworkRuleSetConfig holds the declared firewall rules and rule sets of a server network
+type serverNetworkRuleSetConfig struct {
+	ruleSetStrs   []interface{}
+	ruleSets      []fwu.RuleSet
+	explicitRules gsclient.FirewallRules
+}
+
+// getServerNetworkRuleSetConfigs returns the declared firewall rules and rule sets
+// of the server networks, by network UUID
+func getServerNetworkRuleSetConfigs(d resourceGetter) (map[string]serverNetworkRuleSetConfig, error) {
+	configs := make(map[string]serverNetworkRuleSetConfig)
+	for _, netIntf := range d.Get("network").([]interface{}) {
+		network, ok := netIntf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ruleSetStrs, _ := network["rule_sets"].([]interface{})
+		ruleSets, err := fwu.DecodeRuleSets(convSOStrings(ruleSetStrs))
+		if err != nil {
+			return nil, fmt.Errorf("network (%v): %v", network["object_uuid"], err)
+		}
+		configs[network["object_uuid"].(string)] = serverNetworkRuleSetConfig{
+			ruleSetStrs:   ruleSetStrs,
+			ruleSets:      ruleSets,
+			explicitRules: getExplicitFirewallRules(mapGetter(network)),
+		}
+	}
+	return configs, nil
+}
+
+// getServerNetworkRuleSetConflicts merges the rule sets of all server networks
+// and returns the resolved conflicts
+func getServerNetworkRuleSetConflicts(d resourceGetter) ([]string, error) {
+	var conflicts []string
+	for idx, netIntf := range d.Get("network").([]interface{}) {
+		network, ok := netIntf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ruleSetStrs, _ := network["rule_sets"].([]interface{})
+		ruleSets, err := fwu.DecodeRuleSets(convSOStrings(ruleSetStrs))
+		if err != nil {
+			return nil, fmt.Errorf("network.%d: %v", idx, err)
+		}
+		_, netConflicts := fwu.MergeRuleSets(getExplicitFirewallRules(mapGetter(network)), ruleSets)
+		for _, conflict := range netConflicts {
+			conflicts = append(conflicts, fmt.Sprintf("network.%d (%v): %s", idx, network["object_uuid"], conflict))
+		}
+	}
+	return conflicts, nil
+}
+
+// mapGetter makes a nested block usable as resourceGetter. Missing keys are
+// returned as empty lists, as only rule lists are read through it.
+type mapGetter map[string]interface{}
+
+func (m mapGetter) Get(key string) interface{} {
+	if val, ok := m[key]; ok && val != nil {
+		return val
+	}
+	return []interface{}{}
+}
+
 // flattenFirewallRuleProperties converts variable of type gsclient.FirewallRuleProperties to
 // map[string]interface{}
 func flattenFirewallRuleProperties(props gsclient.FirewallRuleProperties) map[string]interface{} {
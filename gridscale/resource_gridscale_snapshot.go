@@ -6,14 +6,27 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
 
 	"github.com/gridscale/gsclient-go/v3"
 )
 
+// snapshotTerraformLabel is a reserved label marking the snapshots of `gridscale_snapshot` resources,
+// they are never deleted by a `gridscale_snapshot_retention_policy`. It is hidden in `labels`.
+const snapshotTerraformLabel = "#tf#gridscale_snapshot"
+
+// snapshotExportPollInterval is the interval in which the object storage is checked for the exported object
+const snapshotExportPollInterval = 10 * time.Second
+
+// snapshotExportCheckTimeout is the timeout of checking an exported object on refresh
+const snapshotExportCheckTimeout = 1 * time.Minute
+
 func resourceGridscaleStorageSnapshot() *schema.Resource {
 	return &schema.Resource{
 		Read:   resourceGridscaleSnapshotRead,
@@ -133,6 +146,21 @@ the product_no of the license (see the /prices endpoint for more details)`,
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"object_size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Size of the exported object in bytes.",
+						},
+						"etag": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ETag of the exported object.",
+						},
+						"exported_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time (RFC3339) the exported object was last modified.",
+						},
 					},
 				},
 			},
@@ -166,8 +194,8 @@ the product_no of the license (see the /prices endpoint for more details)`,
 			},
 		},
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Update: schema.DefaultTimeout(5 * time.Minute),
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 	}
@@ -188,6 +216,18 @@ func resourceGridscaleSnapshotRead(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
 	props := snapshot.Properties
+	// Snapshots created by earlier versions of the provider or imported snapshots are marked on refresh,
+	// so that they are protected from snapshot retention policies
+	if !hasAnyLabel(props.Labels, []string{snapshotTerraformLabel}) {
+		labels := append(append(make([]string, 0, len(props.Labels)+1), props.Labels...), snapshotTerraformLabel)
+		err = client.UpdateStorageSnapshot(context.Background(), storageUuid, d.Id(), gsclient.StorageSnapshotUpdateRequest{
+			Labels: &labels,
+		})
+		if err != nil {
+			return fmt.Errorf("%s error adding label %s: %v", errorPrefix, snapshotTerraformLabel, err)
+		}
+		log.Printf("[DEBUG] Snapshot (%s) of storage (%s) is labelled with %s", d.Id(), storageUuid, snapshotTerraformLabel)
+	}
 	if err = d.Set("name", props.Name); err != nil {
 		return fmt.Errorf("%s error setting name: %v", errorPrefix, err)
 	}
@@ -225,9 +265,33 @@ func resourceGridscaleSnapshotRead(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("%s error setting capacity: %v", errorPrefix, err)
 	}
 	//Set labels
-	if err = d.Set("labels", props.Labels); err != nil {
+	if err = d.Set("labels", removeSnapshotTerraformLabel(props.Labels)); err != nil {
 		return fmt.Errorf("%s error setting labels: %v", errorPrefix, err)
 	}
+
+	// Exports whose object is missing are removed, so that the next plan exports them again
+	if attr, ok := d.GetOk("object_storage_export"); ok {
+		exports := make([]interface{}, 0)
+		for _, value := range attr.(*schema.Set).List() {
+			exportData := value.(map[string]interface{})
+			ctx, cancel := context.WithTimeout(context.Background(), snapshotExportCheckTimeout)
+			object, err := headSnapshotExport(ctx, exportData)
+			cancel()
+			if err != nil {
+				if isS3NotFoundError(err) {
+					log.Printf("[DEBUG] Exported object %s of snapshot %s is missing in bucket %s", exportData["object"], d.Id(), exportData["bucket"])
+					continue
+				}
+				log.Printf("[WARN] Exported object %s of snapshot %s could not be checked: %v", exportData["object"], d.Id(), err)
+			} else {
+				setSnapshotExportObject(exportData, object)
+			}
+			exports = append(exports, exportData)
+		}
+		if err = d.Set("object_storage_export", exports); err != nil {
+			return fmt.Errorf("%s error setting object_storage_export: %v", errorPrefix, err)
+		}
+	}
 	return nil
 }
 
@@ -236,7 +300,7 @@ func resourceGridscaleSnapshotCreate(d *schema.ResourceData, meta interface{}) e
 	storageUUID := d.Get("storage_uuid").(string)
 	requestBody := gsclient.StorageSnapshotCreateRequest{
 		Name:   d.Get("name").(string),
-		Labels: convSOStrings(d.Get("labels").(*schema.Set).List()),
+		Labels: append(convSOStrings(d.Get("labels").(*schema.Set).List()), snapshotTerraformLabel),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
@@ -245,6 +309,9 @@ func resourceGridscaleSnapshotCreate(d *schema.ResourceData, meta interface{}) e
 	if err != nil {
 		return err
 	}
+	// the ID is set at once, so that the snapshot is not lost when a rollback or an export fails
+	d.SetId(response.ObjectUUID)
+	log.Printf("The id for snapshot %s has been set to %v", requestBody.Name, response.ObjectUUID)
 	errorPrefix := fmt.Sprintf("rollback storage (%s) snapshot (%s) -", storageUUID, response.ObjectUUID)
 
 	//Start rolling back if there are initially requests to rollback
@@ -283,30 +350,9 @@ func resourceGridscaleSnapshotCreate(d *schema.ResourceData, meta interface{}) e
 		requests := make([]interface{}, 0)
 		for _, requestProps := range attr.(*schema.Set).List() {
 			exportReqData := requestProps.(map[string]interface{})
-			objStorageHost := exportReqData["host"].(string)
-			objStorageHostURL, err := url.Parse(objStorageHost)
-			if err != nil {
+			if err = exportSnapshotToS3(ctx, client, storageUUID, response.ObjectUUID, exportReqData); err != nil {
 				return err
 			}
-			exportReqBody := gsclient.StorageSnapshotExportToS3Request{
-				S3auth: gsclient.S3auth{
-					Host:      objStorageHostURL.Host,
-					AccessKey: exportReqData["access_key"].(string),
-					SecretKey: exportReqData["secret_key"].(string),
-				},
-				S3data: gsclient.S3data{
-					Host:     objStorageHost,
-					Bucket:   exportReqData["bucket"].(string),
-					Filename: exportReqData["object"].(string),
-					Private:  exportReqData["private"].(bool),
-				},
-			}
-			err = client.ExportStorageSnapshotToS3(ctx, storageUUID, response.ObjectUUID, exportReqBody)
-			if err != nil {
-				exportReqData["status"] = err.Error()
-			} else {
-				exportReqData["status"] = "success"
-			}
 			requests = append(requests, exportReqData)
 		}
 		//Apply value back to schema
@@ -314,8 +360,6 @@ func resourceGridscaleSnapshotCreate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("%s error setting export: %v", errorPrefix, err)
 		}
 	}
-	d.SetId(response.ObjectUUID)
-	log.Printf("The id for snapshot %s has been set to %v", requestBody.Name, response.ObjectUUID)
 	return resourceGridscaleSnapshotRead(d, meta)
 }
 
@@ -324,7 +368,7 @@ func resourceGridscaleSnapshotUpdate(d *schema.ResourceData, meta interface{}) e
 	storageUUID := d.Get("storage_uuid").(string)
 	errorPrefix := fmt.Sprintf("update snapshot (%s) resource of storage (%s) -", d.Id(), storageUUID)
 
-	labels := convSOStrings(d.Get("labels").(*schema.Set).List())
+	labels := append(convSOStrings(d.Get("labels").(*schema.Set).List()), snapshotTerraformLabel)
 	requestBody := gsclient.StorageSnapshotUpdateRequest{
 		Name:   d.Get("name").(string),
 		Labels: &labels,
@@ -376,30 +420,9 @@ func resourceGridscaleSnapshotUpdate(d *schema.ResourceData, meta interface{}) e
 		for _, requestProps := range attr.(*schema.Set).List() {
 			exportReqData := requestProps.(map[string]interface{})
 			if exportReqData["status"] == "" {
-				objStorageHost := exportReqData["host"].(string)
-				objStorageHostURL, err := url.Parse(objStorageHost)
-				if err != nil {
+				if err = exportSnapshotToS3(ctx, client, storageUUID, d.Id(), exportReqData); err != nil {
 					return err
 				}
-				exportReqBody := gsclient.StorageSnapshotExportToS3Request{
-					S3auth: gsclient.S3auth{
-						Host:      objStorageHostURL.Host,
-						AccessKey: exportReqData["access_key"].(string),
-						SecretKey: exportReqData["secret_key"].(string),
-					},
-					S3data: gsclient.S3data{
-						Host:     objStorageHost,
-						Bucket:   exportReqData["bucket"].(string),
-						Filename: exportReqData["object"].(string),
-						Private:  exportReqData["private"].(bool),
-					},
-				}
-				err = client.ExportStorageSnapshotToS3(ctx, storageUUID, d.Id(), exportReqBody)
-				if err != nil {
-					exportReqData["status"] = err.Error()
-				} else {
-					exportReqData["status"] = "success"
-				}
 			}
 			requests = append(requests, exportReqData)
 		}
@@ -427,3 +450,110 @@ func resourceGridscaleSnapshotDelete(d *schema.ResourceData, meta interface{}) e
 	}
 	return nil
 }
+
+// exportSnapshotToS3 exports a snapshot to the object storage of an `object_storage_export` entry and
+// waits until the exported object has been written. The result is recorded in the computed fields of the entry.
+func exportSnapshotToS3(ctx context.Context, client *gsclient.Client, storageUUID, snapshotUUID string, exportData map[string]interface{}) error {
+	objStorageHost := exportData["host"].(string)
+	objStorageHostURL, err := url.Parse(objStorageHost)
+	if err != nil {
+		return err
+	}
+	exportReqBody := gsclient.StorageSnapshotExportToS3Request{
+		S3auth: gsclient.S3auth{
+			Host:      objStorageHostURL.Host,
+			AccessKey: exportData["access_key"].(string),
+			SecretKey: exportData["secret_key"].(string),
+		},
+		S3data: gsclient.S3data{
+			Host:     objStorageHost,
+			Bucket:   exportData["bucket"].(string),
+			Filename: exportData["object"].(string),
+			Private:  exportData["private"].(bool),
+		},
+	}
+	log.Printf("Start exporting snapshot %s to %s/%s", snapshotUUID, exportData["bucket"], exportData["object"])
+	// An object left by an earlier export is recorded, so that it is not mistaken for the new one.
+	// The clock of the object storage is not compared with the local clock, as they may differ.
+	previous, err := headSnapshotExport(ctx, exportData)
+	if err != nil {
+		if !isS3NotFoundError(err) {
+			return fmt.Errorf("error getting object %s of bucket %s: %v", exportData["object"], exportData["bucket"], err)
+		}
+		previous = nil
+	}
+	if err = client.ExportStorageSnapshotToS3(ctx, storageUUID, snapshotUUID, exportReqBody); err != nil {
+		exportData["status"] = err.Error()
+		return fmt.Errorf("error exporting snapshot %s to %s/%s: %v", snapshotUUID, exportData["bucket"], exportData["object"], err)
+	}
+	object, err := waitForSnapshotExport(ctx, exportData, previous)
+	if err != nil {
+		exportData["status"] = fmt.Sprintf("export could not be verified: %v", err)
+		return fmt.Errorf("error verifying export of snapshot %s to %s/%s: %v", snapshotUUID, exportData["bucket"], exportData["object"], err)
+	}
+	exportData["status"] = "success"
+	setSnapshotExportObject(exportData, object)
+	log.Printf("Exporting snapshot %s to %s/%s SUCCESSFULLY", snapshotUUID, exportData["bucket"], exportData["object"])
+	return nil
+}
+
+// waitForSnapshotExport waits until the exported object of an `object_storage_export` entry has been
+// written. The object which existed before the export (nil if none) is not accepted.
+func waitForSnapshotExport(ctx context.Context, exportData map[string]interface{}, previous *s3.HeadObjectOutput) (*s3.HeadObjectOutput, error) {
+	for {
+		object, err := headSnapshotExport(ctx, exportData)
+		if err != nil && !isS3NotFoundError(err) {
+			return nil, err
+		}
+		if err == nil && snapshotExportChanged(previous, object) {
+			return object, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("object %s has not been written to bucket %s: %v", exportData["object"], exportData["bucket"], ctx.Err())
+		case <-time.After(snapshotExportPollInterval):
+		}
+	}
+}
+
+// headSnapshotExport gets the metadata of the exported object of an `object_storage_export` entry
+func headSnapshotExport(ctx context.Context, exportData map[string]interface{}) (*s3.HeadObjectOutput, error) {
+	s3Client, _ := initS3ClientForEndpoint(exportData["host"].(string), exportData["access_key"].(string), exportData["secret_key"].(string))
+	return s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(exportData["bucket"].(string)),
+		Key:    aws.String(exportData["object"].(string)),
+	})
+}
+
+// snapshotExportChanged checks whether an exported object is not the object which existed before the export
+// (nil if none), i.e. its ETag has changed or it has been modified later
+func snapshotExportChanged(previous, object *s3.HeadObjectOutput) bool {
+	if previous == nil {
+		return true
+	}
+	if aws.ToString(object.ETag) != aws.ToString(previous.ETag) {
+		return true
+	}
+	return object.LastModified != nil && previous.LastModified != nil && object.LastModified.After(*previous.LastModified)
+}
+
+// setSnapshotExportObject records the metadata of the exported object in an `object_storage_export` entry
+func setSnapshotExportObject(exportData map[string]interface{}, object *s3.HeadObjectOutput) {
+	exportData["object_size"] = int(aws.ToInt64(object.ContentLength))
+	exportData["etag"] = strings.Trim(aws.ToString(object.ETag), `"`)
+	exportData["exported_at"] = ""
+	if object.LastModified != nil {
+		exportData["exported_at"] = object.LastModified.UTC().Format(time.RFC3339)
+	}
+}
+
+// removeSnapshotTerraformLabel returns the labels of a snapshot without the reserved snapshotTerraformLabel
+func removeSnapshotTerraformLabel(labels []string) []string {
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != snapshotTerraformLabel {
+			result = append(result, label)
+		}
+	}
+	return result
+}
@@ -0,0 +1,342 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
+)
+
+// snapshotRetentionPolicyInputs are the arguments which select the snapshots to delete
+var snapshotRetentionPolicyInputs = []string{
+	"storage_uuids", "storage_labels", "snapshot_labels", "keep_last", "keep_younger_than", "keep_labels", "protected_snapshot_uuids",
+}
+
+func resourceGridscaleSnapshotRetentionPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceGridscaleSnapshotRetentionPolicyCreate,
+		Read:          resourceGridscaleSnapshotRetentionPolicyRead,
+		Update:        resourceGridscaleSnapshotRetentionPolicyUpdate,
+		Delete:        resourceGridscaleSnapshotRetentionPolicyDelete,
+		CustomizeDiff: resourceGridscaleSnapshotRetentionPolicyCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"storage_uuids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Description:  "UUIDs of the storages whose snapshots are managed by the policy.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{"storage_uuids", "storage_labels"},
+			},
+			"storage_labels": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Description:  "The snapshots of all storages which have at least one of these labels are managed by the policy.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{"storage_uuids", "storage_labels"},
+			},
+			"snapshot_labels": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Only snapshots which have all of these labels are managed by the policy. All other snapshots are kept and do not count for `keep_last`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"keep_last": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of the newest managed snapshots of each storage which are kept.",
+				ValidateFunc: validation.IntAtLeast(1),
+				AtLeastOneOf: []string{"keep_last", "keep_younger_than"},
+			},
+			"keep_younger_than": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Managed snapshots which are younger than this duration (e.g. \"168h\") are kept.",
+				ValidateFunc: validateDuration,
+				AtLeastOneOf: []string{"keep_last", "keep_younger_than"},
+			},
+			"keep_labels": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Snapshots which have at least one of these labels are kept.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"protected_snapshot_uuids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "UUIDs of further snapshots which are never deleted. Snapshots of `gridscale_snapshot` resources are always protected.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"snapshots_to_delete": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Snapshots which are deleted by the next apply.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "UUID of the snapshot.",
+						},
+						"storage_uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "UUID of the storage of the snapshot.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the snapshot.",
+						},
+						"create_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time the snapshot was created.",
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+		},
+	}
+}
+
+// snapshotRetentionRules decide which snapshots of storages are deleted
+type snapshotRetentionRules struct {
+	keepLast        int
+	keepYoungerThan time.Duration
+	snapshotLabels  []string
+	keepLabels      []string
+	// protected snapshots are never deleted, e.g. snapshots of schedules. Snapshots
+	// of `gridscale_snapshot` resources are always protected.
+	protected map[string]bool
+}
+
+// expandSnapshotRetentionRules reads the retention rules of the policy
+func expandSnapshotRetentionRules(d resourceGetter) snapshotRetentionRules {
+	rules := snapshotRetentionRules{
+		keepLast:       d.Get("keep_last").(int),
+		snapshotLabels: convSOStrings(d.Get("snapshot_labels").(*schema.Set).List()),
+		keepLabels:     convSOStrings(d.Get("keep_labels").(*schema.Set).List()),
+		protected:      make(map[string]bool),
+	}
+	if keepYoungerThan := d.Get("keep_younger_than").(string); keepYoungerThan != "" {
+		rules.keepYoungerThan, _ = time.ParseDuration(keepYoungerThan)
+	}
+	for _, uuid := range convSOStrings(d.Get("protected_snapshot_uuids").(*schema.Set).List()) {
+		rules.protected[uuid] = true
+	}
+	return rules
+}
+
+// expired returns the snapshots which are not kept by any rule, ordered by storage and age
+func (r snapshotRetentionRules) expired(snapshots []gsclient.StorageSnapshot, now time.Time) []gsclient.StorageSnapshot {
+	managed := make(map[string][]gsclient.StorageSnapshot)
+	for _, snapshot := range snapshots {
+		props := snapshot.Properties
+		if r.protected[props.ObjectUUID] || hasAnyLabel(props.Labels, []string{snapshotTerraformLabel}) ||
+			!hasAllLabels(props.Labels, r.snapshotLabels) {
+			continue
+		}
+		managed[props.ParentUUID] = append(managed[props.ParentUUID], snapshot)
+	}
+	storageUUIDs := make([]string, 0, len(managed))
+	for storageUUID := range managed {
+		storageUUIDs = append(storageUUIDs, storageUUID)
+	}
+	sort.Strings(storageUUIDs)
+
+	var expired []gsclient.StorageSnapshot
+	for _, storageUUID := range storageUUIDs {
+		storageSnapshots := managed[storageUUID]
+		// newest first
+		sort.SliceStable(storageSnapshots, func(i, j int) bool {
+			return storageSnapshots[i].Properties.CreateTime.After(storageSnapshots[j].Properties.CreateTime.Time)
+		})
+		for i, snapshot := range storageSnapshots {
+			props := snapshot.Properties
+			switch {
+			case i < r.keepLast:
+			case r.keepYoungerThan > 0 && now.Sub(props.CreateTime.Time) < r.keepYoungerThan:
+			case hasAnyLabel(props.Labels, r.keepLabels):
+			default:
+				expired = append(expired, snapshot)
+			}
+		}
+	}
+	return expired
+}
+
+// hasAnyLabel reports whether labels contains at least one of the wanted labels
+func hasAnyLabel(labels, wanted []string) bool {
+	for _, label := range labels {
+		for _, w := range wanted {
+			if label == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// snapshotRetentionPolicyStorages returns the UUIDs of the storages selected by the policy
+func snapshotRetentionPolicyStorages(ctx context.Context, client *gsclient.Client, d resourceGetter) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, uuid := range convSOStrings(d.Get("storage_uuids").(*schema.Set).List()) {
+		selected[uuid] = true
+	}
+	if storageLabels := convSOStrings(d.Get("storage_labels").(*schema.Set).List()); len(storageLabels) > 0 {
+		storages, err := client.GetStorageList(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting storages: %v", err)
+		}
+		for _, storage := range storages {
+			if hasAnyLabel(storage.Properties.Labels, storageLabels) {
+				selected[storage.Properties.ObjectUUID] = true
+			}
+		}
+	}
+	storageUUIDs := make([]string, 0, len(selected))
+	for uuid := range selected {
+		storageUUIDs = append(storageUUIDs, uuid)
+	}
+	sort.Strings(storageUUIDs)
+	return storageUUIDs, nil
+}
+
+// expiredSnapshots lists the snapshots of the storages selected by the policy and returns
+// the ones which are not kept by any rule. Snapshots taken by snapshot schedules are never returned.
+func expiredSnapshots(ctx context.Context, client *gsclient.Client, d resourceGetter) ([]gsclient.StorageSnapshot, error) {
+	storageUUIDs, err := snapshotRetentionPolicyStorages(ctx, client, d)
+	if err != nil {
+		return nil, err
+	}
+	rules := expandSnapshotRetentionRules(d)
+	var snapshots []gsclient.StorageSnapshot
+	for _, storageUUID := range storageUUIDs {
+		storageSnapshots, err := client.GetStorageSnapshotList(ctx, storageUUID)
+		if err != nil {
+			if requestError, ok := err.(gsclient.RequestError); ok && requestError.StatusCode == http.StatusNotFound {
+				log.Printf("[DEBUG] Storage %s of snapshot retention policy does not exist", storageUUID)
+				continue
+			}
+			return nil, fmt.Errorf("error getting snapshots of storage %s: %v", storageUUID, err)
+		}
+		schedules, err := client.GetStorageSnapshotScheduleList(ctx, storageUUID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting snapshot schedules of storage %s: %v", storageUUID, err)
+		}
+		for _, schedule := range schedules {
+			for _, snapshot := range schedule.Properties.Relations.Snapshots {
+				rules.protected[snapshot.ObjectUUID] = true
+			}
+		}
+		for _, snapshot := range storageSnapshots {
+			// group the snapshots by the storage they are listed for
+			snapshot.Properties.ParentUUID = storageUUID
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return rules.expired(snapshots, time.Now()), nil
+}
+
+// flattenSnapshotsToDelete converts snapshots to the `snapshots_to_delete` attribute
+func flattenSnapshotsToDelete(snapshots []gsclient.StorageSnapshot) []interface{} {
+	result := make([]interface{}, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		result = append(result, map[string]interface{}{
+			"object_uuid":  snapshot.Properties.ObjectUUID,
+			"storage_uuid": snapshot.Properties.ParentUUID,
+			"name":         snapshot.Properties.Name,
+			"create_time":  snapshot.Properties.CreateTime.String(),
+		})
+	}
+	return result
+}
+
+// resourceGridscaleSnapshotRetentionPolicyCustomizeDiff shows the snapshots which will be deleted in the plan
+func resourceGridscaleSnapshotRetentionPolicyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range snapshotRetentionPolicyInputs {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("snapshots_to_delete")
+		}
+	}
+	client := meta.(*gsclient.Client)
+	snapshots, err := expiredSnapshots(ctx, client, d)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("snapshots_to_delete", flattenSnapshotsToDelete(snapshots))
+}
+
+func resourceGridscaleSnapshotRetentionPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(id.UniqueId())
+	log.Printf("[DEBUG] The id for snapshot retention policy has been set to %v", d.Id())
+	return resourceGridscaleSnapshotRetentionPolicyApply(d, meta, d.Timeout(schema.TimeoutCreate))
+}
+
+// resourceGridscaleSnapshotRetentionPolicyRead does not read anything, the snapshots to
+// delete are reset so that the next plan shows the currently expired snapshots
+func resourceGridscaleSnapshotRetentionPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	errorPrefix := fmt.Sprintf("read snapshot retention policy (%s) resource -", d.Id())
+	if err := d.Set("snapshots_to_delete", []interface{}{}); err != nil {
+		return fmt.Errorf("%s error setting snapshots_to_delete: %v", errorPrefix, err)
+	}
+	return nil
+}
+
+func resourceGridscaleSnapshotRetentionPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceGridscaleSnapshotRetentionPolicyApply(d, meta, d.Timeout(schema.TimeoutUpdate))
+}
+
+func resourceGridscaleSnapshotRetentionPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	// The remaining snapshots are kept, they are not managed by the policy anymore
+	log.Printf("[DEBUG] The snapshot retention policy %s is removed, the remaining snapshots are kept", d.Id())
+	return nil
+}
+
+// resourceGridscaleSnapshotRetentionPolicyApply deletes the snapshots shown in the plan. Snapshots
+// which are not expired anymore (e.g. a schedule took over the snapshot meanwhile) are kept.
+func resourceGridscaleSnapshotRetentionPolicyApply(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	client := meta.(*gsclient.Client)
+	errorPrefix := fmt.Sprintf("apply snapshot retention policy (%s) resource -", d.Id())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	expired, err := expiredSnapshots(ctx, client, d)
+	if err != nil {
+		return fmt.Errorf("%s %v", errorPrefix, err)
+	}
+	stillExpired := make(map[string]bool)
+	for _, snapshot := range expired {
+		stillExpired[snapshot.Properties.ObjectUUID] = true
+	}
+	for _, value := range d.Get("snapshots_to_delete").([]interface{}) {
+		snapshot := value.(map[string]interface{})
+		snapshotUUID := snapshot["object_uuid"].(string)
+		if !stillExpired[snapshotUUID] {
+			log.Printf("[DEBUG] Snapshot %s is not expired anymore, it is kept", snapshotUUID)
+			continue
+		}
+		err = errHandler.SuppressHTTPErrorCodes(
+			client.DeleteStorageSnapshot(ctx, snapshot["storage_uuid"].(string), snapshotUUID),
+			http.StatusNotFound,
+		)
+		if err != nil {
+			return fmt.Errorf("%s error deleting snapshot %s: %v", errorPrefix, snapshotUUID, err)
+		}
+		log.Printf("[DEBUG] Snapshot %s (%s) has been deleted by the retention policy", snapshotUUID, snapshot["name"])
+	}
+	return nil
+}
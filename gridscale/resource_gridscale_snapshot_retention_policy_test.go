@@ -0,0 +1,111 @@
+package gridscale
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestSnapshotRetentionRulesExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := func(uuid, storageUUID string, age time.Duration, labels ...string) gsclient.StorageSnapshot {
+		return gsclient.StorageSnapshot{Properties: gsclient.StorageSnapshotProperties{
+			ObjectUUID: uuid,
+			ParentUUID: storageUUID,
+			CreateTime: gsclient.GSTime{Time: now.Add(-age)},
+			Labels:     labels,
+		}}
+	}
+	snapshots := []gsclient.StorageSnapshot{
+		snapshot("a1", "a", 1*time.Hour, "pre-deploy"),
+		snapshot("a2", "a", 48*time.Hour, "pre-deploy"),
+		snapshot("a3", "a", 24*time.Hour, "pre-deploy", "keep"),
+		snapshot("a4", "a", 72*time.Hour),
+		snapshot("b1", "b", 96*time.Hour, "pre-deploy"),
+		snapshot("b2", "b", 120*time.Hour, "pre-deploy"),
+		snapshot("b3", "b", 144*time.Hour, "pre-deploy", snapshotTerraformLabel),
+	}
+	type testCase struct {
+		Rules    snapshotRetentionRules
+		Expected []string
+	}
+	testCases := []testCase{
+		{
+			Rules:    snapshotRetentionRules{keepLast: 1},
+			Expected: []string{"a3", "a2", "a4", "b2"},
+		},
+		{
+			// only labeled snapshots are managed and count for keep_last
+			Rules:    snapshotRetentionRules{keepLast: 1, snapshotLabels: []string{"pre-deploy"}},
+			Expected: []string{"a3", "a2", "b2"},
+		},
+		{
+			Rules:    snapshotRetentionRules{keepYoungerThan: 36 * time.Hour, keepLabels: []string{"keep"}},
+			Expected: []string{"a2", "a4", "b1", "b2"},
+		},
+		{
+			// protected snapshots are never deleted and do not count for keep_last
+			Rules:    snapshotRetentionRules{keepLast: 1, protected: map[string]bool{"a1": true, "b2": true}},
+			Expected: []string{"a2", "a4"},
+		},
+		{
+			// snapshots of gridscale_snapshot resources are never deleted, even without being listed
+			Rules:    snapshotRetentionRules{keepLast: 0, keepYoungerThan: time.Hour, snapshotLabels: []string{"pre-deploy"}},
+			Expected: []string{"a1", "a3", "a2", "b1", "b2"},
+		},
+	}
+	for i, test := range testCases {
+		var uuids []string
+		for _, expired := range test.Rules.expired(snapshots, now) {
+			uuids = append(uuids, expired.Properties.ObjectUUID)
+		}
+		if !reflect.DeepEqual(uuids, test.Expected) {
+			t.Errorf("test case %d: expected expired snapshots %v, got %v", i, test.Expected, uuids)
+		}
+	}
+}
+
+func TestAccResourceGridscaleSnapshotRetentionPolicyBasic(t *testing.T) {
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDataSourceGridscaleSnapshotDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleSnapshotRetentionPolicyConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gridscale_snapshot_retention_policy.foo", "id"),
+					resource.TestCheckResourceAttr("gridscale_snapshot_retention_policy.foo", "snapshots_to_delete.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceGridscaleSnapshotRetentionPolicyConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "foo" {
+  name   = "%s"
+  capacity = 1
+}
+
+resource "gridscale_snapshot" "foo" {
+  name = "%s"
+  storage_uuid = gridscale_storage.foo.id
+}
+
+resource "gridscale_snapshot_retention_policy" "foo" {
+  storage_uuids = [gridscale_storage.foo.id]
+  keep_younger_than = "168h"
+  protected_snapshot_uuids = [gridscale_snapshot.foo.id]
+}
+`, name, name)
+}
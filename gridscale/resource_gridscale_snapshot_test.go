@@ -3,8 +3,12 @@ package gridscale
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -12,6 +16,46 @@ import (
 	"github.com/gridscale/gsclient-go/v3"
 )
 
+func TestSetSnapshotExportObject(t *testing.T) {
+	exportData := map[string]interface{}{"status": "success"}
+	setSnapshotExportObject(exportData, &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(1073741824),
+		ETag:          aws.String(`"9b2cf535f27731c974343645a3985328-64"`),
+		LastModified:  aws.Time(time.Date(2025, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))),
+	})
+	expected := map[string]interface{}{
+		"status":      "success",
+		"object_size": 1073741824,
+		"etag":        "9b2cf535f27731c974343645a3985328-64",
+		"exported_at": "2025-03-01T09:30:00Z",
+	}
+	if !reflect.DeepEqual(exportData, expected) {
+		t.Errorf("expected %v, got %v", expected, exportData)
+	}
+}
+
+func TestSnapshotExportChanged(t *testing.T) {
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	previous := &s3.HeadObjectOutput{ETag: aws.String(`"a"`), LastModified: aws.Time(lastModified)}
+	type testCase struct {
+		Previous *s3.HeadObjectOutput
+		Object   *s3.HeadObjectOutput
+		Expected bool
+	}
+	testCases := []testCase{
+		{nil, &s3.HeadObjectOutput{ETag: aws.String(`"a"`)}, true},
+		{previous, &s3.HeadObjectOutput{ETag: aws.String(`"a"`), LastModified: aws.Time(lastModified)}, false},
+		{previous, &s3.HeadObjectOutput{ETag: aws.String(`"b"`), LastModified: aws.Time(lastModified)}, true},
+		{previous, &s3.HeadObjectOutput{ETag: aws.String(`"a"`), LastModified: aws.Time(lastModified.Add(time.Second))}, true},
+		{previous, &s3.HeadObjectOutput{ETag: aws.String(`"a"`)}, false},
+	}
+	for i, test := range testCases {
+		if changed := snapshotExportChanged(test.Previous, test.Object); changed != test.Expected {
+			t.Errorf("case %d: expected %v, got %v", i, test.Expected, changed)
+		}
+	}
+}
+
 func TestAccResourceGridscaleSnapshotBasic(t *testing.T) {
 	var object gsclient.StorageSnapshot
 	name := fmt.Sprintf("object-%s", acctest.RandString(10))
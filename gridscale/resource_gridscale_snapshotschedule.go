@@ -16,7 +16,7 @@ import (
 )
 
 func resourceGridscaleStorageSnapshotSchedule() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceGridscaleSnapshotScheduleCreate,
 		Read:   resourceGridscaleSnapshotScheduleRead,
 		Delete: resourceGridscaleSnapshotScheduleDelete,
@@ -24,6 +24,7 @@ func resourceGridscaleStorageSnapshotSchedule() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeScheduleCronDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -48,9 +49,11 @@ func resourceGridscaleStorageSnapshotSchedule() *schema.Resource {
 			},
 			"run_interval": {
 				Type:         schema.TypeInt,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.IntAtLeast(60),
 				Description:  "The interval at which the schedule will run (in minutes)",
+				AtLeastOneOf: []string{"run_interval", "cron"},
 			},
 			"storage_uuid": {
 				Type:        schema.TypeString,
@@ -107,6 +110,8 @@ func resourceGridscaleStorageSnapshotSchedule() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 	}
+	addScheduleCronSchema(r.Schema)
+	return r
 }
 
 func resourceGridscaleSnapshotScheduleRead(d *schema.ResourceData, meta interface{}) error {
@@ -184,6 +189,14 @@ func resourceGridscaleSnapshotScheduleCreate(d *schema.ResourceData, meta interf
 		}
 		requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
 	}
+	nextRuntime, runInterval, isCron, err := scheduleCronRuntime(d, time.Now())
+	if err != nil {
+		return err
+	}
+	if isCron {
+		requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
+		requestBody.RunInterval = runInterval
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
 	defer cancel()
@@ -222,10 +235,19 @@ func resourceGridscaleSnapshotScheduleUpdate(d *schema.ResourceData, meta interf
 			requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
 		}
 	}
+	// The runtime of a cron expression is always sent, as it is only updated when it drifted
+	nextRuntime, runInterval, isCron, err := scheduleCronRuntime(d, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s error: %v", errorPrefix, err)
+	}
+	if isCron {
+		requestBody.NextRuntime = &gsclient.GSTime{Time: nextRuntime}
+		requestBody.RunInterval = runInterval
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
-	err := client.UpdateStorageSnapshotSchedule(ctx, storageUUID, d.Id(), requestBody)
+	err = client.UpdateStorageSnapshotSchedule(ctx, storageUUID, d.Id(), requestBody)
 	if err != nil {
 		return fmt.Errorf("%s error: %v", errorPrefix, err)
 	}
@@ -8,7 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	certu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/cert-utils"
 	errHandler "github.com/terraform-providers/terraform-provider-gridscale/gridscale/error-handler"
 
 	"github.com/gridscale/gsclient-go/v3"
@@ -16,12 +19,49 @@ import (
 
 func resourceGridscaleSSLCert() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGridscaleSSLCertCreate,
-		Read:   resourceGridscaleSSLCertRead,
-		Delete: resourceGridscaleSSLCertDelete,
+		CreateContext: resourceGridscaleSSLCertCreate,
+		ReadContext:   resourceGridscaleSSLCertRead,
+		UpdateContext: resourceGridscaleSSLCertUpdate,
+		DeleteContext: resourceGridscaleSSLCertDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			// Only new certificates have to be checked, all certificate arguments force a new resource
+			if d.Id() != "" && !d.HasChanges("private_key", "leaf_certificate", "certificate_chain") {
+				return nil
+			}
+			if !d.NewValueKnown("private_key") || !d.NewValueKnown("leaf_certificate") || !d.NewValueKnown("certificate_chain") {
+				return nil
+			}
+			leafCert := d.Get("leaf_certificate").(string)
+			if leafCert == "" {
+				// the certificate is generated on apply
+				return nil
+			}
+			bundle, err := certu.ParseBundle(d.Get("private_key").(string), leafCert, d.Get("certificate_chain").(string), d.Get("validate_chain").(bool))
+			if err != nil {
+				return err
+			}
+			if time.Now().After(bundle.Leaf.NotAfter) {
+				return fmt.Errorf("leaf_certificate expired at %s", bundle.Leaf.NotAfter.UTC().Format(time.RFC3339))
+			}
+			// Compute the attributes locally, so that they are known at plan time
+			if err = d.SetNew("common_name", bundle.Leaf.Subject.CommonName); err != nil {
+				return err
+			}
+			if err = d.SetNew("not_valid_after", bundle.Leaf.NotAfter.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+			fingerprints := certu.CertificateFingerprints(bundle.Leaf)
+			return d.SetNew("fingerprints", []interface{}{
+				map[string]interface{}{
+					"md5":    fingerprints.MD5,
+					"sha256": fingerprints.SHA256,
+					"sha1":   fingerprints.SHA1,
+				},
+			})
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -31,35 +71,112 @@ func resourceGridscaleSSLCert() *schema.Resource {
 				ForceNew:    true,
 			},
 			"private_key": {
-				Type:        schema.TypeString,
-				Description: "The PEM-formatted private-key of the SSL certificate.",
-				Required:    true,
-				ForceNew:    true,
-				Sensitive:   true,
+				Type:          schema.TypeString,
+				Description:   "The PEM-formatted private-key of the SSL certificate.",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				Sensitive:     true,
+				RequiredWith:  []string{"leaf_certificate"},
+				ConflictsWith: []string{"generate_self_signed"},
 				StateFunc: func(val interface{}) string {
 					return strings.TrimSpace((val.(string)))
 				},
 			},
 			"leaf_certificate": {
-				Type:        schema.TypeString,
-				Description: "The PEM-formatted public SSL of the SSL certificate.",
-				Required:    true,
-				ForceNew:    true,
-				Sensitive:   true,
+				Type:         schema.TypeString,
+				Description:  "The PEM-formatted public SSL of the SSL certificate.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"private_key"},
+				ExactlyOneOf: []string{"leaf_certificate", "generate_self_signed"},
 				StateFunc: func(val interface{}) string {
 					return strings.TrimSpace((val.(string)))
 				},
 			},
 			"certificate_chain": {
-				Type:        schema.TypeString,
-				Description: "The PEM-formatted full-chain between the certificate authority and the domain's SSL certificate.",
-				Optional:    true,
-				ForceNew:    true,
-				Sensitive:   true,
+				Type:          schema.TypeString,
+				Description:   "The PEM-formatted full-chain between the certificate authority and the domain's SSL certificate.",
+				Optional:      true,
+				ForceNew:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"generate_self_signed"},
 				StateFunc: func(val interface{}) string {
 					return strings.TrimSpace((val.(string)))
 				},
 			},
+			"generate_self_signed": {
+				Type:        schema.TypeList,
+				Description: "Generates a private key and a self-signed certificate, e.g. for internal load balancers.",
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"common_name": {
+							Type:         schema.TypeString,
+							Description:  "The common name of the subject. It is added to the DNS names, unless it is an IP address.",
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"organization": {
+							Type:        schema.TypeString,
+							Description: "The organization of the subject.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"dns_names": {
+							Type:        schema.TypeList,
+							Description: "DNS names of the subject alternative names.",
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"ip_addresses": {
+							Type:        schema.TypeList,
+							Description: "IP addresses of the subject alternative names.",
+							Optional:    true,
+							ForceNew:    true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.IsIPAddress,
+							},
+						},
+						"key_type": {
+							Type:         schema.TypeString,
+							Description:  "The type of the generated private key.",
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "rsa2048",
+							ValidateFunc: validation.StringInSlice(certu.KeyTypes, false),
+						},
+						"validity_days": {
+							Type:         schema.TypeInt,
+							Description:  "The number of days the certificate is valid.",
+							Optional:     true,
+							ForceNew:     true,
+							Default:      365,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"validate_chain": {
+				Type:        schema.TypeBool,
+				Description: "Whether the certificate chain is checked to be ordered and complete before the certificate is uploaded.",
+				Optional:    true,
+				Default:     true,
+			},
+			"expiry_warning_days": {
+				Type:         schema.TypeInt,
+				Description:  "A warning is raised if the certificate expires within this number of days. 0 disables the warning.",
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 			"common_name": {
 				Type:        schema.TypeString,
 				Description: "The common domain name of the SSL certificate.",
@@ -124,7 +241,31 @@ func resourceGridscaleSSLCert() *schema.Resource {
 	}
 }
 
-func resourceGridscaleSSLCertRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGridscaleSSLCertRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := resourceGridscaleSSLCertReadState(d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+	if d.Id() == "" {
+		return diags
+	}
+	warningDays := d.Get("expiry_warning_days").(int)
+	notValidAfter, err := time.Parse(time.RFC3339, d.Get("not_valid_after").(string))
+	if warningDays > 0 && err == nil && time.Until(notValidAfter) < time.Duration(warningDays)*24*time.Hour {
+		summary := "SSL certificate expires soon"
+		if time.Now().After(notValidAfter) {
+			summary = "SSL certificate expired"
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  summary,
+			Detail:   fmt.Sprintf("SSL certificate %q (%s) is not valid after %s", d.Get("name").(string), d.Id(), notValidAfter.Format(time.RFC3339)),
+		})
+	}
+	return diags
+}
+
+func resourceGridscaleSSLCertReadState(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*gsclient.Client)
 	errorPrefix := fmt.Sprintf("read SSL Certificate (%s) resource -", d.Id())
 	cert, err := client.GetSSLCertificate(context.Background(), d.Id())
@@ -172,11 +313,37 @@ func resourceGridscaleSSLCertRead(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceGridscaleSSLCertCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGridscaleSSLCertCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*gsclient.Client)
+	errorPrefix := "create SSL Certificate resource -"
 	privKey := d.Get("private_key").(string)
 	leafCert := d.Get("leaf_certificate").(string)
 	certChain := d.Get("certificate_chain").(string)
+
+	if generateList := d.Get("generate_self_signed").([]interface{}); len(generateList) > 0 {
+		generate := generateList[0].(map[string]interface{})
+		var err error
+		privKey, leafCert, err = certu.GenerateSelfSigned(certu.SelfSignedOptions{
+			CommonName:   generate["common_name"].(string),
+			Organization: generate["organization"].(string),
+			DNSNames:     convSOStrings(generate["dns_names"].([]interface{})),
+			IPAddresses:  convSOStrings(generate["ip_addresses"].([]interface{})),
+			KeyType:      generate["key_type"].(string),
+			Validity:     time.Duration(generate["validity_days"].(int)) * 24 * time.Hour,
+		})
+		if err != nil {
+			return diag.Errorf("%s error generating self-signed certificate: %v", errorPrefix, err)
+		}
+		if err = d.Set("private_key", privKey); err != nil {
+			return diag.Errorf("%s error setting private_key: %v", errorPrefix, err)
+		}
+		if err = d.Set("leaf_certificate", leafCert); err != nil {
+			return diag.Errorf("%s error setting leaf_certificate: %v", errorPrefix, err)
+		}
+	} else if _, err := certu.ParseBundle(privKey, leafCert, certChain, d.Get("validate_chain").(bool)); err != nil {
+		// values which were unknown at plan time are checked here
+		return diag.Errorf("%s %v", errorPrefix, err)
+	}
 	requestBody := gsclient.SSLCertificateCreateRequest{
 		Name:             d.Get("name").(string),
 		PrivateKey:       strings.TrimSpace(privKey),
@@ -185,32 +352,38 @@ func resourceGridscaleSSLCertCreate(d *schema.ResourceData, meta interface{}) er
 		Labels:           convSOStrings(d.Get("labels").(*schema.Set).List()),
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
 	defer cancel()
 	response, err := client.CreateSSLCertificate(ctx, requestBody)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(response.ObjectUUID)
 
 	log.Printf("The id for the new SSL Certificate has been set to %v", response.ObjectUUID)
 
-	return resourceGridscaleSSLCertRead(d, meta)
+	return resourceGridscaleSSLCertRead(ctx, d, meta)
 }
 
-func resourceGridscaleSSLCertDelete(d *schema.ResourceData, meta interface{}) error {
+// resourceGridscaleSSLCertUpdate only updates local settings (validate_chain, expiry_warning_days),
+// all other arguments force a new resource
+func resourceGridscaleSSLCertUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceGridscaleSSLCertRead(ctx, d, meta)
+}
+
+func resourceGridscaleSSLCertDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*gsclient.Client)
 	errorPrefix := fmt.Sprintf("delete SSL Certificate (%s) resource -", d.Id())
 
-	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutDelete))
 	defer cancel()
 	err := errHandler.SuppressHTTPErrorCodes(
 		client.DeleteSSLCertificate(ctx, d.Id()),
 		http.StatusNotFound,
 	)
 	if err != nil {
-		return fmt.Errorf("%s error: %v", errorPrefix, err)
+		return diag.Errorf("%s error: %v", errorPrefix, err)
 	}
 	return nil
 }
@@ -52,6 +52,30 @@ func TestAccResourceGridscaleSSLCertBasic(t *testing.T) {
 	})
 }
 
+func TestAccResourceGridscaleSSLCertSelfSigned(t *testing.T) {
+	var object gsclient.SSLCertificate
+	name := fmt.Sprintf("object-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGridscaleSSLCertDestroyCheck,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceGridscaleSSLCertConfigSelfSigned(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceGridscaleSSLCertExists("gridscale_ssl_certificate.foo", &object),
+					resource.TestCheckResourceAttr(
+						"gridscale_ssl_certificate.foo", "common_name", "lb.internal"),
+					resource.TestCheckResourceAttrSet("gridscale_ssl_certificate.foo", "private_key"),
+					resource.TestCheckResourceAttrSet("gridscale_ssl_certificate.foo", "leaf_certificate"),
+					resource.TestCheckResourceAttrSet("gridscale_ssl_certificate.foo", "not_valid_after"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckResourceGridscaleSSLCertExists(n string, object *gsclient.SSLCertificate) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -117,3 +141,18 @@ resource "gridscale_ssl_certificate" "foo" {
 }
 `, name)
 }
+
+func testAccCheckResourceGridscaleSSLCertConfigSelfSigned(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_ssl_certificate" "foo" {
+  name   = "%s"
+  generate_self_signed {
+    common_name = "lb.internal"
+    dns_names = ["www.lb.internal"]
+    key_type = "ecdsa_p256"
+    validity_days = 30
+  }
+  expiry_warning_days = 7
+}
+`, name)
+}
@@ -22,6 +22,7 @@ func resourceGridscaleStorageImport() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateStorageImportBackup,
 
 		Schema: map[string]*schema.Schema{
 			"storage_backup_id": {
@@ -31,6 +32,20 @@ func resourceGridscaleStorageImport() *schema.Resource {
 				Description:  "ID of the storage backup that will be used to create a new storage from.",
 				ValidateFunc: validation.NoZeroValues,
 			},
+			"source_storage_uuid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "UUID of the storage the backup belongs to. If set, the plan checks that the backup exists.",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"allowed_backup_location_uuids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Description:  "UUIDs of the backup locations the backup may be stored in. If set, the plan checks the location of the backup when the storage is created.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"source_storage_uuid"},
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Description: "The human-readable name of the object. It supports the full UTF-8 character set, with a maximum of 64 characters.",
@@ -163,3 +178,70 @@ func resourceGridscaleStorageImportFromBackup(d *schema.ResourceData, meta inter
 
 	return resourceGridscaleStorageRead(d, meta)
 }
+
+// validateStorageImportBackup checks that the backup of a new storage import exists in the backups of
+// its source storage and that it is stored in one of the allowed backup locations
+func validateStorageImportBackup(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("storage_backup_id") || !d.NewValueKnown("source_storage_uuid") ||
+		!d.NewValueKnown("allowed_backup_location_uuids") {
+		return nil
+	}
+	storageUUID := d.Get("source_storage_uuid").(string)
+	if storageUUID == "" {
+		return nil
+	}
+	client := meta.(*gsclient.Client)
+	backupUUID := d.Get("storage_backup_id").(string)
+	backups, err := client.GetStorageBackupList(ctx, storageUUID)
+	if err != nil {
+		return fmt.Errorf("error getting backups of storage %s: %v", storageUUID, err)
+	}
+	found := false
+	for _, backup := range backups {
+		if backup.Properties.ObjectUUID == backupUUID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("storage backup %s does not exist in the backups of storage %s", backupUUID, storageUUID)
+	}
+
+	allowedLocations := convSOStrings(d.Get("allowed_backup_location_uuids").(*schema.Set).List())
+	if len(allowedLocations) == 0 {
+		return nil
+	}
+	schedules, err := client.GetStorageBackupScheduleList(ctx, storageUUID)
+	if err != nil {
+		return fmt.Errorf("error getting backup schedules of storage %s: %v", storageUUID, err)
+	}
+	return checkStorageBackupLocation(schedules, backupUUID, allowedLocations)
+}
+
+// checkStorageBackupLocation checks that a backup is stored in one of the allowed backup locations
+func checkStorageBackupLocation(schedules []gsclient.StorageBackupSchedule, backupUUID string, allowedLocations []string) error {
+	locationUUID, ok := storageBackupLocation(schedules, backupUUID)
+	if !ok {
+		return fmt.Errorf("the backup location of storage backup %s can not be determined, the backup schedule which took it does not exist anymore", backupUUID)
+	}
+	for _, allowed := range allowedLocations {
+		if allowed == locationUUID {
+			return nil
+		}
+	}
+	return fmt.Errorf("storage backup %s is stored in backup location %s, which is not in allowed_backup_location_uuids (%s)",
+		backupUUID, locationUUID, strings.Join(allowedLocations, ", "))
+}
+
+// storageBackupLocation returns the backup location of a backup, which is the location of the
+// backup schedule that took it
+func storageBackupLocation(schedules []gsclient.StorageBackupSchedule, backupUUID string) (string, bool) {
+	for _, schedule := range schedules {
+		for _, backup := range schedule.Properties.Relations.StorageBackups {
+			if backup.ObjectUUID == backupUUID {
+				return schedule.Properties.BackupLocationUUID, true
+			}
+		}
+	}
+	return "", false
+}
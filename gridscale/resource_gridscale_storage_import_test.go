@@ -0,0 +1,66 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/gridscale/gsclient-go/v3"
+)
+
+func TestStorageBackupLocation(t *testing.T) {
+	schedule := func(locationUUID string, backupUUIDs ...string) gsclient.StorageBackupSchedule {
+		var backups []gsclient.StorageBackupScheduleRelation
+		for _, backupUUID := range backupUUIDs {
+			backups = append(backups, gsclient.StorageBackupScheduleRelation{ObjectUUID: backupUUID})
+		}
+		return gsclient.StorageBackupSchedule{Properties: gsclient.StorageBackupScheduleProperties{
+			BackupLocationUUID: locationUUID,
+			Relations:          gsclient.StorageBackupScheduleRelations{StorageBackups: backups},
+		}}
+	}
+	schedules := []gsclient.StorageBackupSchedule{
+		schedule("location-1", "backup-1", "backup-2"),
+		schedule("location-2", "backup-3"),
+	}
+	type testCase struct {
+		BackupUUID string
+		Expected   string
+		Found      bool
+	}
+	testCases := []testCase{
+		{"backup-2", "location-1", true},
+		{"backup-3", "location-2", true},
+		{"backup-4", "", false},
+	}
+	for _, test := range testCases {
+		locationUUID, found := storageBackupLocation(schedules, test.BackupUUID)
+		if locationUUID != test.Expected || found != test.Found {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", test.BackupUUID, test.Expected, test.Found, locationUUID, found)
+		}
+	}
+}
+
+func TestCheckStorageBackupLocation(t *testing.T) {
+	schedules := []gsclient.StorageBackupSchedule{{Properties: gsclient.StorageBackupScheduleProperties{
+		BackupLocationUUID: "location-1",
+		Relations: gsclient.StorageBackupScheduleRelations{
+			StorageBackups: []gsclient.StorageBackupScheduleRelation{{ObjectUUID: "backup-1"}},
+		},
+	}}}
+	type testCase struct {
+		BackupUUID       string
+		AllowedLocations []string
+		Valid            bool
+	}
+	testCases := []testCase{
+		{"backup-1", []string{"location-1"}, true},
+		{"backup-1", []string{"location-2", "location-1"}, true},
+		{"backup-1", []string{"location-2"}, false},
+		{"backup-2", []string{"location-1"}, false},
+	}
+	for i, test := range testCases {
+		err := checkStorageBackupLocation(schedules, test.BackupUUID, test.AllowedLocations)
+		if (err == nil) != test.Valid {
+			t.Errorf("case %d: expected valid %v, got error %v", i, test.Valid, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gridscale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	cronu "github.com/terraform-providers/terraform-provider-gridscale/gridscale/cron-utils"
+)
+
+// addScheduleCronSchema adds the `cron` and `timezone` attributes to the schema of a snapshot or backup
+// schedule. `cron` replaces `next_runtime` and `run_interval`, which are computed from the expression.
+func addScheduleCronSchema(s map[string]*schema.Schema) {
+	s["cron"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Description:   "Cron expression (minute hour day-of-month month day-of-week) of the schedule, e.g. \"30 2 * * *\". It is converted to `next_runtime` and `run_interval`, so its runs have to be evenly spaced.",
+		ValidateFunc:  validateCronExpression,
+		ConflictsWith: []string{"next_runtime", "run_interval"},
+	}
+	s["timezone"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "Time zone (e.g. \"Europe/Berlin\") `cron` is evaluated in. Defaults to UTC.",
+		ValidateFunc: validateTimezone,
+		RequiredWith: []string{"cron"},
+	}
+}
+
+// validateCronExpression validates a cron expression which can be represented by a run interval
+func validateCronExpression(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := cronu.Parse(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s is invalid: %v", k, err))
+	}
+	return
+}
+
+// validateTimezone validates the name of a time zone
+func validateTimezone(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.LoadLocation(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s is not a valid time zone: %v", k, err))
+	}
+	return
+}
+
+// scheduleCronRuntime returns the next runtime (UTC) and the run interval of the cron expression of a
+// schedule. ok is false, if the schedule has no cron expression.
+func scheduleCronRuntime(d resourceGetter, now time.Time) (nextRuntime time.Time, interval int, ok bool, err error) {
+	expr := d.Get("cron").(string)
+	if expr == "" {
+		return time.Time{}, 0, false, nil
+	}
+	schedule, err := cronu.Parse(expr)
+	if err != nil {
+		return time.Time{}, 0, false, err
+	}
+	// an empty time zone is UTC
+	loc, err := time.LoadLocation(d.Get("timezone").(string))
+	if err != nil {
+		return time.Time{}, 0, false, err
+	}
+	return schedule.Next(now, loc).UTC(), schedule.Interval(), true, nil
+}
+
+// customizeScheduleCronDiff computes `run_interval` and `next_runtime_computed` from the cron expression.
+// A schedule whose next runtime does not match the expression anymore (e.g. after a DST change or a
+// change outside of terraform) is shown as a change.
+func customizeScheduleCronDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("cron") || !d.NewValueKnown("timezone") {
+		return nil
+	}
+	nextRuntime, interval, ok, err := scheduleCronRuntime(d, time.Now())
+	if err != nil || !ok {
+		return err
+	}
+	if d.Get("run_interval").(int) != interval {
+		if err = d.SetNew("run_interval", interval); err != nil {
+			return err
+		}
+	}
+	if expected := nextRuntime.Format(timeLayout); d.Get("next_runtime_computed").(string) != expected {
+		return d.SetNew("next_runtime_computed", expected)
+	}
+	return nil
+}
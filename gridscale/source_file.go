@@ -0,0 +1,295 @@
+package gridscale
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// sourceFilePartSize is the size of the parts of a multipart upload of a source file
+const sourceFilePartSize = 64 * 1024 * 1024
+
+// sourceFileTarget describes the attribute of a resource which is replaced by an uploaded `source_file`
+type sourceFileTarget struct {
+	// attribute is the name of the attribute the uploaded file is passed to the API with
+	attribute string
+	// presign is true if the API pulls the file from a presigned URL, otherwise it is passed as s3:// path
+	presign bool
+	// forceNew is true if a new upload requires a new object
+	forceNew bool
+}
+
+// addSchema adds the `source_file` block and the `source_file_sha256` attribute to the schema of a resource.
+// The target attribute has to be optional and computed.
+func (t sourceFileTarget) addSchema(s map[string]*schema.Schema) {
+	fileSchema := map[string]*schema.Schema{
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "Path of the local file.",
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"bucket_name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "Name of the Object Storage bucket the file is uploaded to.",
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Key of the uploaded object. Defaults to the name of the file.",
+		},
+		"access_key": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Sensitive:   true,
+			Description: "Access key of the Object Storage.",
+		},
+		"secret_key": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Sensitive:   true,
+			Description: "Secret key of the Object Storage.",
+		},
+		"s3_host": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "gos3.io",
+			Description: "The S3 host.",
+		},
+	}
+	if t.presign {
+		fileSchema["url_expiry"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "1h",
+			Description:  "Duration (e.g. \"30m\") the presigned URL the file is pulled from is valid for.",
+			ValidateFunc: validateDuration,
+		}
+	}
+	s["source_file"] = &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		ForceNew:     t.forceNew,
+		MaxItems:     1,
+		Description:  fmt.Sprintf("Local file which is uploaded to Object Storage (multipart) instead of setting `%s`.", t.attribute),
+		ExactlyOneOf: []string{t.attribute, "source_file"},
+		Elem:         &schema.Resource{Schema: fileSchema},
+	}
+	s["source_file_sha256"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "SHA-256 checksum (hex) of the uploaded `source_file`.",
+	}
+}
+
+// sourceFile is the expanded `source_file` block
+type sourceFile struct {
+	path      string
+	bucket    string
+	key       string
+	accessKey string
+	secretKey string
+	s3Host    string
+	urlExpiry time.Duration
+}
+
+// expandSourceFile returns the `source_file` block of a resource. ok is false, if it is not set.
+func expandSourceFile(d resourceGetter) (file sourceFile, ok bool) {
+	list, _ := d.Get("source_file").([]interface{})
+	if len(list) == 0 || list[0] == nil {
+		return file, false
+	}
+	block := list[0].(map[string]interface{})
+	file = sourceFile{
+		path:      block["path"].(string),
+		bucket:    block["bucket_name"].(string),
+		key:       block["key"].(string),
+		accessKey: block["access_key"].(string),
+		secretKey: block["secret_key"].(string),
+		s3Host:    block["s3_host"].(string),
+	}
+	if file.key == "" {
+		file.key = filepath.Base(file.path)
+	}
+	if expiry, ok := block["url_expiry"].(string); ok {
+		file.urlExpiry, _ = time.ParseDuration(expiry)
+	}
+	return file, true
+}
+
+// objectPath returns the s3:// path of the uploaded file
+func (f sourceFile) objectPath() string {
+	return fmt.Sprintf("s3://%s/%s", f.bucket, f.key)
+}
+
+// fileSHA256 returns the SHA-256 checksum (hex) of a local file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// customizeDiff plans a new upload, when the checksum of the local file differs from the uploaded one
+func (t sourceFileTarget) customizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// the file may be created by another resource during apply
+	if !d.NewValueKnown("source_file") {
+		return nil
+	}
+	file, ok := expandSourceFile(d)
+	if !ok {
+		return nil
+	}
+	checksum, err := fileSHA256(file.path)
+	// an uploaded file may be removed locally afterwards
+	if os.IsNotExist(err) && d.Id() != "" {
+		log.Printf("[WARN] source_file %s does not exist anymore, the uploaded file is kept", file.path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading source_file: %v", err)
+	}
+	if !t.presign && d.Get(t.attribute).(string) != file.objectPath() {
+		if err = d.SetNew(t.attribute, file.objectPath()); err != nil {
+			return err
+		}
+	}
+	if d.Get("source_file_sha256").(string) == checksum {
+		return nil
+	}
+	if err = d.SetNew("source_file_sha256", checksum); err != nil {
+		return err
+	}
+	if t.forceNew && d.Id() != "" {
+		if err = d.ForceNew("source_file_sha256"); err != nil {
+			return err
+		}
+	}
+	if t.presign {
+		return d.SetNewComputed(t.attribute)
+	}
+	return nil
+}
+
+// upload uploads the `source_file` of a resource and returns the value of the target attribute,
+// i.e. a presigned URL or the s3:// path of the object
+func (t sourceFileTarget) upload(ctx context.Context, d *schema.ResourceData) (string, error) {
+	file, _ := expandSourceFile(d)
+	client, _ := initS3ClientForEndpoint(file.s3Host, file.accessKey, file.secretKey)
+	checksum, err := uploadSourceFile(ctx, client, file)
+	if err != nil {
+		return "", fmt.Errorf("error uploading source_file %s to bucket %s: %v", file.path, file.bucket, err)
+	}
+	// the file must not change between plan and upload
+	if planned := d.Get("source_file_sha256").(string); planned != "" && planned != checksum {
+		return "", fmt.Errorf("source_file %s changed after plan, SHA-256 is %s instead of %s", file.path, checksum, planned)
+	}
+	if err = d.Set("source_file_sha256", checksum); err != nil {
+		return "", fmt.Errorf("error setting source_file_sha256: %v", err)
+	}
+	if !t.presign {
+		return file.objectPath(), nil
+	}
+	request, err := s3.NewPresignClient(client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(file.bucket),
+		Key:    aws.String(file.key),
+	}, s3.WithPresignExpires(file.urlExpiry))
+	if err != nil {
+		return "", fmt.Errorf("error presigning URL of source_file %s: %v", file.path, err)
+	}
+	return request.URL, nil
+}
+
+// uploadSourceFile uploads a local file with a multipart upload and returns its SHA-256 checksum (hex)
+func uploadSourceFile(ctx context.Context, client *s3.Client, file sourceFile) (string, error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err != nil {
+		return "", err
+	} else if info.Size() == 0 {
+		return "", fmt.Errorf("file is empty")
+	}
+
+	upload, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(file.bucket),
+		Key:    aws.String(file.key),
+	})
+	if err != nil {
+		return "", err
+	}
+	abort := func(err error) (string, error) {
+		_, errAbort := client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(file.bucket),
+			Key:      aws.String(file.key),
+			UploadId: upload.UploadId,
+		})
+		if errAbort != nil {
+			log.Printf("[WARN] Aborting upload of %s failed: %v", file.path, errAbort)
+		}
+		return "", err
+	}
+
+	hash := sha256.New()
+	buf := make([]byte, sourceFilePartSize)
+	var parts []types.CompletedPart
+	for partNumber := int32(1); ; partNumber++ {
+		n, err := io.ReadFull(f, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return abort(err)
+		}
+		hash.Write(buf[:n])
+		part, errUpload := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(file.bucket),
+			Key:        aws.String(file.key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if errUpload != nil {
+			return abort(fmt.Errorf("error uploading part %d: %v", partNumber, errUpload))
+		}
+		parts = append(parts, types.CompletedPart{ETag: part.ETag, PartNumber: aws.Int32(partNumber)})
+		log.Printf("[DEBUG] Part %d of %s is uploaded", partNumber, file.path)
+		// the last part is shorter than the part size
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(file.bucket),
+		Key:             aws.String(file.key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return abort(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,83 @@
+package gridscale
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFileSHA256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.iso")
+	if err := os.WriteFile(path, []byte("gridscale"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "55a6c5bf6567e273dad77feac7b1d0a3e097e94233f9ed143a82ff30b6a23604"; checksum != expected {
+		t.Errorf("expected %s, got %s", expected, checksum)
+	}
+	if _, err = fileSHA256(filepath.Join(t.TempDir(), "missing.iso")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
+
+func TestExpandSourceFile(t *testing.T) {
+	r := resourceGridscaleISOImage()
+	type testCase struct {
+		Block      map[string]interface{}
+		ObjectPath string
+		URLExpiry  time.Duration
+	}
+	testCases := []testCase{
+		{
+			Block: map[string]interface{}{
+				"path":        "/tmp/build/image.iso",
+				"bucket_name": "images",
+				"access_key":  "ak",
+				"secret_key":  "sk",
+			},
+			ObjectPath: "s3://images/image.iso",
+			URLExpiry:  time.Hour,
+		},
+		{
+			Block: map[string]interface{}{
+				"path":        "/tmp/build/image.iso",
+				"bucket_name": "images",
+				"key":         "isos/rescue-1.2.iso",
+				"access_key":  "ak",
+				"secret_key":  "sk",
+				"url_expiry":  "15m",
+			},
+			ObjectPath: "s3://images/isos/rescue-1.2.iso",
+			URLExpiry:  15 * time.Minute,
+		},
+	}
+	for i, test := range testCases {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"name":        "rescue",
+			"source_file": []interface{}{test.Block},
+		})
+		file, ok := expandSourceFile(d)
+		if !ok {
+			t.Fatalf("case %d: expected source_file to be set", i)
+		}
+		if file.objectPath() != test.ObjectPath {
+			t.Errorf("case %d: expected object path %q, got %q", i, test.ObjectPath, file.objectPath())
+		}
+		if file.urlExpiry != test.URLExpiry {
+			t.Errorf("case %d: expected URL expiry %v, got %v", i, test.URLExpiry, file.urlExpiry)
+		}
+		if file.s3Host != "gos3.io" {
+			t.Errorf("case %d: expected default s3_host, got %q", i, file.s3Host)
+		}
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "rescue"})
+	if _, ok := expandSourceFile(d); ok {
+		t.Errorf("expected source_file not to be set")
+	}
+}
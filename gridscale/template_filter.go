@@ -0,0 +1,175 @@
+package gridscale
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gridscale/gsclient-go/v3"
+	goVersion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// templateFilterKeys are the attributes templates can be selected by
+var templateFilterKeys = []string{"name", "name_regex", "distro", "ostype", "version_constraint", "private", "labels"}
+
+// addTemplateFilterSchema adds the attributes templates can be selected by (except `name`)
+// to the schema of a template data source
+func addTemplateFilterSchema(s map[string]*schema.Schema) {
+	s["name_regex"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "Only templates whose name matches this regular expression are selected.",
+		ValidateFunc: validation.StringIsValidRegExp,
+	}
+	s["distro"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		Description:  "Only templates of this OS distribution (e.g. \"ubuntu\") are selected. The case is ignored.",
+		ValidateFunc: validation.NoZeroValues,
+	}
+	s["ostype"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		Description:  "Only templates of this operating system type (e.g. \"linux\") are selected. The case is ignored.",
+		ValidateFunc: validation.NoZeroValues,
+	}
+	s["version_constraint"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "Only templates whose version satisfies this constraint (e.g. \">= 22.04, < 26.0\") are selected.",
+		ValidateFunc: validateVersionConstraint,
+	}
+	s["private"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+		Description: "Only private (true) or public (false) templates are selected.",
+	}
+	s["labels"] = &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Computed:    true,
+		Description: "Only templates having all of these labels are selected.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// validateVersionConstraint validates a version constraint
+func validateVersionConstraint(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := goVersion.NewConstraint(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s is not a valid version constraint: %v", k, err))
+	}
+	return
+}
+
+// templateFilter selects templates
+type templateFilter struct {
+	name              string
+	nameRegex         *regexp.Regexp
+	distro            string
+	ostype            string
+	versionConstraint goVersion.Constraints
+	private           *bool
+	labels            []string
+}
+
+// expandTemplateFilter reads the template filter of a data source
+func expandTemplateFilter(d *schema.ResourceData) (templateFilter, error) {
+	var filter templateFilter
+	var err error
+	filter.name = d.Get("name").(string)
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		if filter.nameRegex, err = regexp.Compile(nameRegex.(string)); err != nil {
+			return filter, fmt.Errorf("invalid name_regex: %v", err)
+		}
+	}
+	filter.distro = d.Get("distro").(string)
+	filter.ostype = d.Get("ostype").(string)
+	if versionConstraint, ok := d.GetOk("version_constraint"); ok {
+		if filter.versionConstraint, err = goVersion.NewConstraint(versionConstraint.(string)); err != nil {
+			return filter, fmt.Errorf("invalid version_constraint: %v", err)
+		}
+	}
+	// private is only a filter, if it is set explicitly
+	if !d.GetRawConfig().GetAttr("private").IsNull() {
+		private := d.Get("private").(bool)
+		filter.private = &private
+	}
+	if labels, ok := d.GetOk("labels"); ok {
+		filter.labels = convSOStrings(labels.(*schema.Set).List())
+	}
+	return filter, nil
+}
+
+// matches checks whether a template is selected by the filter
+func (f templateFilter) matches(props gsclient.TemplateProperties) bool {
+	if f.name != "" && props.Name != f.name {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(props.Name) {
+		return false
+	}
+	if f.distro != "" && !strings.EqualFold(props.Distro, f.distro) {
+		return false
+	}
+	if f.ostype != "" && !strings.EqualFold(props.Ostype, f.ostype) {
+		return false
+	}
+	if f.versionConstraint != nil {
+		// templates without a parsable version can not satisfy a constraint
+		version, err := goVersion.NewVersion(props.Version)
+		if err != nil || !f.versionConstraint.Check(version) {
+			return false
+		}
+	}
+	if f.private != nil && props.Private != *f.private {
+		return false
+	}
+	return hasAllLabels(props.Labels, f.labels)
+}
+
+// nameOnly checks whether the filter selects templates by name only
+func (f templateFilter) nameOnly() bool {
+	return f.name != "" && f.nameRegex == nil && f.distro == "" && f.ostype == "" &&
+		f.versionConstraint == nil && f.private == nil && len(f.labels) == 0
+}
+
+// selectTemplate returns the single template matching the filter. If several templates match, the most
+// recent one is returned if mostRecent is true. If only the name is filtered, the first template with
+// the name is returned, as the data source did before it supported filters.
+func selectTemplate(templates []gsclient.Template, filter templateFilter, mostRecent bool) (gsclient.Template, error) {
+	if filter.nameOnly() && !mostRecent {
+		for _, template := range templates {
+			if template.Properties.Name == filter.name {
+				return template, nil
+			}
+		}
+	}
+	selected := selectTemplates(templates, filter)
+	if len(selected) == 0 {
+		return gsclient.Template{}, fmt.Errorf("no template matches the selection")
+	}
+	if len(selected) > 1 && !mostRecent {
+		return gsclient.Template{}, fmt.Errorf("%d templates match the selection, narrow it down or set most_recent to true", len(selected))
+	}
+	return selected[0], nil
+}
+
+// selectTemplates returns the templates matching the filter, the most recent template first
+func selectTemplates(templates []gsclient.Template, filter templateFilter) []gsclient.Template {
+	selected := make([]gsclient.Template, 0)
+	for _, template := range templates {
+		if filter.matches(template.Properties) {
+			selected = append(selected, template)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Properties.CreateTime.After(selected[j].Properties.CreateTime.Time)
+	})
+	return selected
+}
@@ -0,0 +1,124 @@
+package gridscale
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gridscale/gsclient-go/v3"
+	goVersion "github.com/hashicorp/go-version"
+)
+
+func TestSelectTemplates(t *testing.T) {
+	template := func(uuid, name, distro, version string, private bool, createTime string, labels ...string) gsclient.Template {
+		created, _ := time.Parse(time.RFC3339, createTime)
+		return gsclient.Template{Properties: gsclient.TemplateProperties{
+			ObjectUUID: uuid,
+			Name:       name,
+			Distro:     distro,
+			Ostype:     "linux",
+			Version:    version,
+			Private:    private,
+			Labels:     labels,
+			CreateTime: gsclient.GSTime{Time: created},
+		}}
+	}
+	templates := []gsclient.Template{
+		template("1", "Ubuntu 22.04 LTS (Jammy Jellyfish) ", "ubuntu", "22.04", false, "2022-05-01T00:00:00Z"),
+		template("2", "Ubuntu 24.04 LTS (Noble Numbat)", "ubuntu", "24.04", false, "2024-05-01T00:00:00Z"),
+		template("3", "Debian 12", "debian", "12", false, "2023-07-01T00:00:00Z"),
+		template("4", "web-1.2.0", "ubuntu", "", true, "2026-10-01T00:00:00Z", "golden-image"),
+	}
+	private, public := true, false
+	type testCase struct {
+		Filter   templateFilter
+		Expected []string
+	}
+	testCases := []testCase{
+		{
+			Filter:   templateFilter{},
+			Expected: []string{"4", "2", "3", "1"},
+		},
+		{
+			Filter:   templateFilter{name: "Debian 12"},
+			Expected: []string{"3"},
+		},
+		{
+			Filter:   templateFilter{distro: "Ubuntu"},
+			Expected: []string{"4", "2", "1"},
+		},
+		{
+			Filter:   templateFilter{distro: "ubuntu", private: &public},
+			Expected: []string{"2", "1"},
+		},
+		{
+			Filter:   templateFilter{ostype: "LINUX", private: &private},
+			Expected: []string{"4"},
+		},
+		{
+			Filter:   templateFilter{distro: "ubuntu", versionConstraint: goVersion.MustConstraints(goVersion.NewConstraint(">= 22.10"))},
+			Expected: []string{"2"},
+		},
+		{
+			Filter:   templateFilter{nameRegex: regexp.MustCompile(`^Ubuntu \d+\.04 LTS`)},
+			Expected: []string{"2", "1"},
+		},
+		{
+			Filter:   templateFilter{labels: []string{"golden-image"}},
+			Expected: []string{"4"},
+		},
+		{
+			Filter:   templateFilter{distro: "centos"},
+			Expected: []string{},
+		},
+	}
+	for i, test := range testCases {
+		selected := make([]string, 0)
+		for _, template := range selectTemplates(templates, test.Filter) {
+			selected = append(selected, template.Properties.ObjectUUID)
+		}
+		if !reflect.DeepEqual(selected, test.Expected) {
+			t.Errorf("case %d: expected %v, got %v", i, test.Expected, selected)
+		}
+	}
+}
+
+func TestSelectTemplate(t *testing.T) {
+	template := func(uuid, name string, private bool, createTime string) gsclient.Template {
+		created, _ := time.Parse(time.RFC3339, createTime)
+		return gsclient.Template{Properties: gsclient.TemplateProperties{
+			ObjectUUID: uuid,
+			Name:       name,
+			Private:    private,
+			CreateTime: gsclient.GSTime{Time: created},
+		}}
+	}
+	// a public and a private template share the name "Debian 12"
+	templates := []gsclient.Template{
+		template("1", "Debian 12", false, "2023-07-01T00:00:00Z"),
+		template("2", "Debian 12", true, "2025-01-01T00:00:00Z"),
+		template("3", "Ubuntu 24.04 LTS (Noble Numbat)", false, "2024-05-01T00:00:00Z"),
+	}
+	private := true
+	type testCase struct {
+		Filter     templateFilter
+		MostRecent bool
+		Expected   string
+		Valid      bool
+	}
+	testCases := []testCase{
+		// only the name is set: the first template with the name is selected
+		{Filter: templateFilter{name: "Debian 12"}, Expected: "1", Valid: true},
+		{Filter: templateFilter{name: "Debian 12"}, MostRecent: true, Expected: "2", Valid: true},
+		{Filter: templateFilter{name: "Debian 12", private: &private}, Expected: "2", Valid: true},
+		{Filter: templateFilter{nameRegex: regexp.MustCompile(`^Debian`)}, Valid: false},
+		{Filter: templateFilter{name: "Debian 11"}, Valid: false},
+	}
+	for i, test := range testCases {
+		selected, err := selectTemplate(templates, test.Filter, test.MostRecent)
+		if (err == nil) != test.Valid || err == nil && selected.Properties.ObjectUUID != test.Expected {
+			t.Errorf("case %d: expected (%q, valid %v), got (%q, %v)", i, test.Expected, test.Valid, selected.Properties.ObjectUUID, err)
+		}
+	}
+}